@@ -0,0 +1,371 @@
+// Package bdd compiles condition logic into reduced ordered binary decision
+// diagrams. Every function of the leaves has exactly one diagram for a given
+// variable order, which makes equivalence, satisfiability and counting the
+// matching assignments cheap once the diagram is built.
+package bdd
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/skuid/balsa/parse"
+)
+
+// Ref points at a node in a Manager. False and True are the two terminals.
+type Ref int
+
+// Define the terminal nodes
+const (
+	False Ref = iota
+	True
+)
+
+type node struct {
+	level int
+	low   Ref
+	high  Ref
+}
+
+type opKey struct {
+	op   string
+	a, b Ref
+}
+
+// Manager holds every node built so far, shared between all of the diagrams it
+// builds. Refs from one Manager mean nothing to another.
+type Manager struct {
+	order  []uint
+	levels map[uint]int
+	nodes  []node
+	unique map[node]Ref
+	cache  map[opKey]Ref
+}
+
+// New makes a Manager that puts the given leaves at the top of its diagrams,
+// in order. Any other leaf is added below them the first time it is seen. A
+// good order keeps leaves that are used together close to each other.
+func New(order ...uint) *Manager {
+	m := &Manager{
+		levels: map[uint]int{},
+		// The terminals sit below every variable, see level
+		nodes:  []node{{}, {}},
+		unique: map[node]Ref{},
+		cache:  map[opKey]Ref{},
+	}
+	for _, v := range order {
+		m.addVar(v)
+	}
+	return m
+}
+
+// Order is the current variable order, from the top of the diagram down
+func (m *Manager) Order() []uint {
+	return append([]uint{}, m.order...)
+}
+
+func (m *Manager) addVar(v uint) int {
+	if l, ok := m.levels[v]; ok {
+		return l
+	}
+	m.levels[v] = len(m.order)
+	m.order = append(m.order, v)
+	return m.levels[v]
+}
+
+func (m *Manager) level(r Ref) int {
+	if r == False || r == True {
+		return len(m.order)
+	}
+	return m.nodes[r].level
+}
+
+// mk returns the node for the level, sharing it if it already exists and
+// skipping it if both branches are the same
+func (m *Manager) mk(level int, low Ref, high Ref) Ref {
+	if low == high {
+		return low
+	}
+
+	n := node{level, low, high}
+	if r, ok := m.unique[n]; ok {
+		return r
+	}
+
+	r := Ref(len(m.nodes))
+	m.nodes = append(m.nodes, n)
+	m.unique[n] = r
+	return r
+}
+
+// Var is the diagram that is true exactly when the leaf is
+func (m *Manager) Var(leaf uint) Ref {
+	return m.mk(m.addVar(leaf), False, True)
+}
+
+// Compile will build the diagram for a tree. An empty tree never matches.
+//	m := bdd.New()
+//	a, _ := parse.Parse("1 AND 2 OR 3")
+//	b, _ := parse.Parse("3 OR (2 AND 1)")
+//	m.Compile(a) == m.Compile(b)
+// Will be true, since both trees mean the same thing
+func (m *Manager) Compile(n parse.Node) Ref {
+	switch node := n.(type) {
+	case *parse.Leaf:
+		return m.Var(node.Val)
+	case *parse.Op:
+		return m.Apply(node.Val, m.Compile(node.Left), m.Compile(node.Right))
+	case *parse.Group:
+		if len(node.Nodes) == 0 {
+			return False
+		}
+		r := m.Compile(node.Nodes[0])
+		for _, c := range node.Nodes[1:] {
+			r = m.Apply(node.Val, r, m.Compile(c))
+		}
+		return r
+	}
+	return False
+}
+
+// And is the diagram for a AND b
+func (m *Manager) And(a Ref, b Ref) Ref {
+	return m.Apply("AND", a, b)
+}
+
+// Or is the diagram for a OR b
+func (m *Manager) Or(a Ref, b Ref) Ref {
+	return m.Apply("OR", a, b)
+}
+
+// Not is the diagram that is true exactly when a is false
+func (m *Manager) Not(a Ref) Ref {
+	switch a {
+	case False:
+		return True
+	case True:
+		return False
+	}
+
+	key := opKey{"NOT", a, a}
+	if r, ok := m.cache[key]; ok {
+		return r
+	}
+
+	n := m.nodes[a]
+	r := m.mk(n.level, m.Not(n.low), m.Not(n.high))
+	m.cache[key] = r
+	return r
+}
+
+// Apply will combine two diagrams with an operation, which is AND or OR the
+// same as in the logic. Anything else is treated as OR, like parse.Match does.
+func (m *Manager) Apply(op string, a Ref, b Ref) Ref {
+	and := op == "AND"
+
+	// Terminal cases
+	switch {
+	case a == b:
+		return a
+	case and && (a == False || b == False):
+		return False
+	case !and && (a == True || b == True):
+		return True
+	case a == False || a == True:
+		return b
+	case b == False || b == True:
+		return a
+	}
+
+	// Both operations are commutative, so share the cache either way around
+	if a > b {
+		a, b = b, a
+	}
+	if and {
+		op = "AND"
+	} else {
+		op = "OR"
+	}
+	key := opKey{op, a, b}
+	if r, ok := m.cache[key]; ok {
+		return r
+	}
+
+	level, al, ah, bl, bh := m.split(a, b)
+	r := m.mk(level, m.Apply(op, al, bl), m.Apply(op, ah, bh))
+	m.cache[key] = r
+	return r
+}
+
+// split finds the top level of a and b and both of their branches there
+func (m *Manager) split(a Ref, b Ref) (int, Ref, Ref, Ref, Ref) {
+	la, lb := m.level(a), m.level(b)
+	level := la
+	if lb < level {
+		level = lb
+	}
+
+	al, ah := a, a
+	if la == level {
+		al, ah = m.nodes[a].low, m.nodes[a].high
+	}
+	bl, bh := b, b
+	if lb == level {
+		bl, bh = m.nodes[b].low, m.nodes[b].high
+	}
+	return level, al, ah, bl, bh
+}
+
+// Restrict will fix a leaf to a value and return the diagram for what is left
+func (m *Manager) Restrict(a Ref, leaf uint, value bool) Ref {
+	level, ok := m.levels[leaf]
+	if !ok {
+		return a
+	}
+	return m.restrict(a, level, value, map[Ref]Ref{})
+}
+
+func (m *Manager) restrict(a Ref, level int, value bool, memo map[Ref]Ref) Ref {
+	if m.level(a) > level {
+		return a
+	}
+	if r, ok := memo[a]; ok {
+		return r
+	}
+
+	n := m.nodes[a]
+	var r Ref
+	if n.level == level {
+		if value {
+			r = n.high
+		} else {
+			r = n.low
+		}
+	} else {
+		r = m.mk(n.level, m.restrict(n.low, level, value, memo), m.restrict(n.high, level, value, memo))
+	}
+	memo[a] = r
+	return r
+}
+
+// Eval will follow the diagram using the given leaf values
+func (m *Manager) Eval(a Ref, value func(uint) bool) bool {
+	for a != False && a != True {
+		n := m.nodes[a]
+		if value(m.order[n.level]) {
+			a = n.high
+		} else {
+			a = n.low
+		}
+	}
+	return a == True
+}
+
+// Count is the number of assignments of every leaf the Manager knows about
+// that make the diagram true
+func (m *Manager) Count(a Ref) *big.Int {
+	c := m.count(a, map[Ref]*big.Int{})
+	return new(big.Int).Lsh(c, uint(m.level(a)))
+}
+
+// count is the number of assignments of the leaves from a's level down
+func (m *Manager) count(a Ref, memo map[Ref]*big.Int) *big.Int {
+	switch a {
+	case False:
+		return big.NewInt(0)
+	case True:
+		return big.NewInt(1)
+	}
+	if c, ok := memo[a]; ok {
+		return c
+	}
+
+	n := m.nodes[a]
+	low := new(big.Int).Lsh(m.count(n.low, memo), uint(m.level(n.low)-n.level-1))
+	high := new(big.Int).Lsh(m.count(n.high, memo), uint(m.level(n.high)-n.level-1))
+	c := low.Add(low, high)
+	memo[a] = c
+	return c
+}
+
+// Size is the number of nodes in the diagram, not counting the terminals
+func (m *Manager) Size(a Ref) int {
+	seen := map[Ref]bool{}
+	var walk func(Ref)
+	walk = func(r Ref) {
+		if r == False || r == True || seen[r] {
+			return
+		}
+		seen[r] = true
+		walk(m.nodes[r].low)
+		walk(m.nodes[r].high)
+	}
+	walk(a)
+	return len(seen)
+}
+
+// Node will turn the diagram back into a tree. Each node becomes
+// "low OR (leaf AND high)", which only works if the diagram never needs a leaf
+// to be false, since there is no NOT. It returns an error for any other
+// diagram, including the terminals.
+func (m *Manager) Node(a Ref) (parse.Node, error) {
+	switch a {
+	case False:
+		return nil, &Error{Reason: "logic that never matches can't be written as a tree"}
+	case True:
+		return nil, &Error{Reason: "logic that always matches can't be written as a tree"}
+	}
+
+	if !m.monotone(a, map[Ref]bool{}) {
+		return nil, &Error{Reason: "logic that needs a leaf to be false can't be written as a tree"}
+	}
+
+	return m.node(a), nil
+}
+
+// monotone checks that turning any leaf on never turns the result off
+func (m *Manager) monotone(a Ref, seen map[Ref]bool) bool {
+	if a == False || a == True || seen[a] {
+		return true
+	}
+	seen[a] = true
+
+	n := m.nodes[a]
+	if m.And(n.low, m.Not(n.high)) != False {
+		return false
+	}
+	return m.monotone(n.low, seen) && m.monotone(n.high, seen)
+}
+
+// node builds the tree for a monotone diagram. Since low implies high, the
+// high branch can never be False and the low branch can never be True.
+func (m *Manager) node(a Ref) parse.Node {
+	n := m.nodes[a]
+
+	var high parse.Node = &parse.Leaf{Val: m.order[n.level]}
+	if n.high != True {
+		high = &parse.Op{
+			Left:  high,
+			Val:   "AND",
+			Right: m.node(n.high),
+		}
+	}
+
+	if n.low == False {
+		return high
+	}
+
+	return &parse.Op{
+		Left:  m.node(n.low),
+		Val:   "OR",
+		Right: high,
+	}
+}
+
+// Error holds information about a diagram that could not be used
+type Error struct {
+	Reason string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Could not convert diagram. Reason: %s", e.Reason)
+}
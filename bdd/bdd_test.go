@@ -0,0 +1,179 @@
+package bdd
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/skuid/balsa/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompile(t *testing.T) {
+	cases := []struct {
+		desc  string
+		a     string
+		b     string
+		equal bool
+	}{
+		{
+			"Should share the same logic",
+			"1 AND 2",
+			"1 AND 2",
+			true,
+		},
+		{
+			"Should share commuted logic",
+			"1 AND 2 OR 3",
+			"3 OR (2 AND 1)",
+			true,
+		},
+		{
+			"Should share logic that only looks different",
+			"1 AND (1 OR 2)",
+			"1",
+			true,
+		},
+		{
+			"Should share distributed logic",
+			"1 AND (2 OR 3)",
+			"1 AND 2 OR (1 AND 3)",
+			true,
+		},
+		{
+			"Should not share different logic",
+			"1 OR 2 AND 3",
+			"1 OR (2 AND 3)",
+			false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			m := New()
+			assert.Equal(c.equal, m.Compile(mustParse(c.a)) == m.Compile(mustParse(c.b)))
+			assert.Equal(c.equal, m.Compile(parse.Flatten(mustParse(c.a))) == m.Compile(mustParse(c.b)))
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	assert := assert.New(t)
+	m := New()
+
+	one, two := m.Var(1), m.Var(2)
+
+	assert.Equal(False, m.And(one, m.Not(one)))
+	assert.Equal(True, m.Or(one, m.Not(one)))
+	assert.Equal(one, m.Not(m.Not(one)))
+	assert.Equal(m.Compile(mustParse("1 AND 2")), m.And(two, one))
+	assert.Equal(m.Compile(mustParse("1 OR 2")), m.Apply("OR", one, two))
+
+	// De Morgan
+	assert.Equal(m.Not(m.And(one, two)), m.Or(m.Not(one), m.Not(two)))
+}
+
+func TestRestrict(t *testing.T) {
+	assert := assert.New(t)
+	m := New()
+
+	r := m.Compile(mustParse("1 AND 2 OR 3"))
+	assert.Equal(m.Compile(mustParse("2 OR 3")), m.Restrict(r, 1, true))
+	assert.Equal(m.Var(3), m.Restrict(r, 1, false))
+	assert.Equal(True, m.Restrict(r, 3, true))
+	assert.Equal(r, m.Restrict(r, 9, true))
+}
+
+func TestCount(t *testing.T) {
+	assert := assert.New(t)
+	m := New(1, 2, 3)
+
+	assert.Equal(big.NewInt(4), m.Count(m.Var(2)))
+	assert.Equal(big.NewInt(5), m.Count(m.Compile(mustParse("1 AND 2 OR 3"))))
+	assert.Equal(big.NewInt(1), m.Count(m.Compile(mustParse("1 AND 2 AND 3"))))
+	assert.Equal(big.NewInt(7), m.Count(m.Compile(mustParse("1 OR 2 OR 3"))))
+	assert.Equal(big.NewInt(0), m.Count(False))
+	assert.Equal(big.NewInt(8), m.Count(True))
+
+	// Counts should not overflow with lots of leaves
+	wide := New()
+	r := False
+	for i := uint(0); i < 200; i++ {
+		r = wide.Or(r, wide.Var(i))
+	}
+	expected := new(big.Int).Lsh(big.NewInt(1), 200)
+	expected.Sub(expected, big.NewInt(1))
+	assert.Equal(0, expected.Cmp(wide.Count(r)))
+}
+
+func TestOrder(t *testing.T) {
+	assert := assert.New(t)
+	n := mustParse("(1 AND 2) OR (3 AND 4) OR (5 AND 6)")
+
+	good := New(1, 2, 3, 4, 5, 6)
+	bad := New(1, 3, 5, 2, 4, 6)
+
+	assert.Equal(6, good.Size(good.Compile(n)))
+	assert.Equal(14, bad.Size(bad.Compile(n)))
+	assert.Equal([]uint{1, 3, 5, 2, 4, 6}, bad.Order())
+}
+
+func TestNode(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		m := New()
+		n := randomTree(r, 4, 6)
+		d := m.Compile(n)
+		if d == False || d == True {
+			continue
+		}
+
+		back, err := m.Node(d)
+		assert.NoError(t, err)
+		assert.Equal(t, d, m.Compile(back), "Expected %v to come back the same from %v", n, back)
+
+		for bits := 0; bits < 1<<6; bits++ {
+			value := func(v uint) bool { return bits&(1<<v) != 0 }
+			assert.Equal(t, parse.Match(n, value), m.Eval(d, value))
+		}
+	}
+}
+
+func TestNodeErrors(t *testing.T) {
+	assert := assert.New(t)
+	m := New()
+
+	_, err := m.Node(False)
+	assert.Equal(&Error{Reason: "logic that never matches can't be written as a tree"}, err)
+
+	_, err = m.Node(True)
+	assert.Equal(&Error{Reason: "logic that always matches can't be written as a tree"}, err)
+
+	_, err = m.Node(m.And(m.Var(1), m.Not(m.Var(2))))
+	assert.Equal(&Error{Reason: "logic that needs a leaf to be false can't be written as a tree"}, err)
+}
+
+func mustParse(logic string) parse.Node {
+	n, err := parse.Parse(logic)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func randomTree(r *rand.Rand, depth int, leaves int) parse.Node {
+	if depth == 0 || r.Intn(3) == 0 {
+		return &parse.Leaf{Val: uint(r.Intn(leaves))}
+	}
+	op := "AND"
+	if r.Intn(2) == 0 {
+		op = "OR"
+	}
+	return &parse.Op{
+		Left:  randomTree(r, depth-1, leaves),
+		Val:   op,
+		Right: randomTree(r, depth-1, leaves),
+	}
+}
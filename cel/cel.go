@@ -0,0 +1,210 @@
+// Package cel converts condition logic to and from Common Expression Language
+// source. It only deals with the part of CEL that condition logic needs: &&,
+// ||, parentheses and identifiers that stand for leaves. A ! is rejected with
+// an error, since condition logic has no negation. No CEL runtime is involved,
+// policies are generated and parsed as plain strings.
+package cel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/skuid/balsa/parse"
+)
+
+// Renderer returns the CEL source for a leaf
+type Renderer func(leaf uint) (string, error)
+
+// Resolver finds the leaf an identifier stands for, returning false if it
+// doesn't stand for one
+type Resolver func(ident string) (uint, bool)
+
+// Ident is the default Renderer. It turns leaf 3 into c3.
+func Ident(leaf uint) (string, error) {
+	return fmt.Sprintf("c%d", leaf), nil
+}
+
+// ResolveIdent is the default Resolver. It turns c3 into leaf 3.
+func ResolveIdent(ident string) (uint, bool) {
+	if !strings.HasPrefix(ident, "c") {
+		return 0, false
+	}
+
+	v, err := strconv.ParseUint(ident[1:], 10, 0)
+	if err != nil {
+		return 0, false
+	}
+	return uint(v), true
+}
+
+// Export will write the tree as a CEL expression, where each chain of ANDs or
+// ORs becomes one run of && or ||. A run inside another one always gets
+// parentheses, even where CEL would read it the same way without them, and so
+// does a leaf with an &&, || or ?: of its own.
+//	n, _ := parse.Parse("1 OR 2 AND 3")
+//	cel.Export(n, cel.Ident)
+// Will yield "(c1 || c2) && c3"
+func Export(n parse.Node, leaf Renderer) (string, error) {
+	expr, err := parse.Fold(n, func(l *parse.Leaf) (interface{}, error) {
+		return leaf(l.Val)
+	}, func(g *parse.Group, exprs []interface{}) (interface{}, error) {
+		op := " || "
+		if g.Val == "AND" {
+			op = " && "
+		}
+
+		parts := make([]string, len(exprs))
+		for i, e := range exprs {
+			parts[i] = e.(string)
+			if _, group := g.Nodes[i].(*parse.Group); group || loose(parts[i]) {
+				parts[i] = "(" + parts[i] + ")"
+			}
+		}
+		return strings.Join(parts, op), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return expr.(string), nil
+}
+
+// loose reports whether the expression has &&, || or the ternary ?: in it,
+// none of which bind tighter than the && they would sit next to
+func loose(expr string) bool {
+	return strings.Contains(expr, "&&") || strings.Contains(expr, "||") || strings.Contains(expr, "?")
+}
+
+// Parse will read a CEL expression made of &&, ||, parentheses and
+// identifiers. && binds tighter than ||, the way CEL reads it, and the tree
+// that comes back means the same thing. Errors are ParseErrors with the
+// position in the expression.
+//	cel.Parse("c1 || c2 && c3", cel.ResolveIdent)
+// Will yield 1 <- OR -> (2 <- AND -> 3)
+func Parse(expr string, resolve Resolver) (parse.Node, error) {
+	p := &parser{
+		expr:    expr,
+		resolve: resolve,
+	}
+	p.next()
+
+	n, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tok != "" {
+		return nil, p.fail(fmt.Sprintf("unexpected %s", p.tok))
+	}
+
+	return parse.Binarize(n), nil
+}
+
+type parser struct {
+	expr    string
+	resolve Resolver
+	// tok is the current token and pos is where it starts. tok is empty at the
+	// end of the expression.
+	tok string
+	pos int
+	end int
+}
+
+func (p *parser) fail(reason string) error {
+	return &parse.ParseError{
+		Position: p.pos,
+		Logic:    p.expr,
+		Reason:   reason,
+	}
+}
+
+// next moves on to the next token
+func (p *parser) next() {
+	i := p.end
+	for i < len(p.expr) && unicode.IsSpace(rune(p.expr[i])) {
+		i++
+	}
+
+	p.pos = i
+	p.end = i
+	if i == len(p.expr) {
+		p.tok = ""
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(p.expr[i:], "&&"), strings.HasPrefix(p.expr[i:], "||"):
+		p.end = i + 2
+	case ident(rune(p.expr[i]), true):
+		for p.end < len(p.expr) && ident(rune(p.expr[p.end]), false) {
+			p.end++
+		}
+	default:
+		p.end = i + 1
+	}
+	p.tok = p.expr[i:p.end]
+}
+
+func ident(r rune, first bool) bool {
+	return r == '_' || (r < unicode.MaxASCII && unicode.IsLetter(r)) || (!first && r < unicode.MaxASCII && unicode.IsDigit(r))
+}
+
+func (p *parser) or() (parse.Node, error) {
+	return p.chain("||", "OR", p.and)
+}
+
+func (p *parser) and() (parse.Node, error) {
+	return p.chain("&&", "AND", p.primary)
+}
+
+// chain reads operands joined by tok into a single group
+func (p *parser) chain(tok string, op string, operand func() (parse.Node, error)) (parse.Node, error) {
+	g := &parse.Group{Val: op}
+	for {
+		n, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		g.Nodes = append(g.Nodes, n)
+
+		if p.tok != tok {
+			break
+		}
+		p.next()
+	}
+
+	if len(g.Nodes) == 1 {
+		return g.Nodes[0], nil
+	}
+	return g, nil
+}
+
+func (p *parser) primary() (parse.Node, error) {
+	switch {
+	case p.tok == "":
+		return nil, p.fail("unexpected end of expression")
+	case p.tok == "!":
+		return nil, p.fail("! is not supported, since condition logic has no negation")
+	case p.tok == "(":
+		p.next()
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok != ")" {
+			return nil, p.fail("unbalanced parenthesis")
+		}
+		p.next()
+		return n, nil
+	case ident(rune(p.tok[0]), true):
+		v, ok := p.resolve(p.tok)
+		if !ok {
+			return nil, p.fail(fmt.Sprintf("%s is not a condition", p.tok))
+		}
+		p.next()
+		return &parse.Leaf{Val: v}, nil
+	}
+
+	return nil, p.fail(fmt.Sprintf("unexpected %s", p.tok))
+}
@@ -0,0 +1,220 @@
+package cel
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/skuid/balsa/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExport(t *testing.T) {
+	cases := []struct {
+		desc     string
+		logic    string
+		expected string
+	}{
+		{
+			"Should export a single leaf",
+			"1",
+			"c1",
+		},
+		{
+			"Should flatten a chain of ANDs",
+			"1 AND 2 AND (3 AND 4)",
+			"c1 && c2 && c3 && c4",
+		},
+		{
+			"Should parenthesize a left group",
+			"1 OR 2 AND 3",
+			"(c1 || c2) && c3",
+		},
+		{
+			"Should parenthesize a right group",
+			"1 OR (2 AND 3)",
+			"c1 || (c2 && c3)",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			n, err := parse.Parse(c.logic)
+			assert.NoError(t, err)
+
+			actual, err := Export(n, Ident)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, actual)
+
+			back, err := Parse(actual, ResolveIdent)
+			assert.NoError(t, err)
+			assert.Equal(t, parse.Hash(n), parse.Hash(back))
+		})
+	}
+}
+
+func TestExportLooseLeaves(t *testing.T) {
+	leaves := map[uint]string{
+		1: "a == 1",
+		2: "x || y",
+		3: "n > 0 ? a : b",
+		4: "name == 'a && b'",
+	}
+	render := func(leaf uint) (string, error) {
+		return leaves[leaf], nil
+	}
+
+	cases := []struct {
+		desc     string
+		logic    string
+		expected string
+	}{
+		{
+			"Should parenthesize a leaf with || in it",
+			"1 AND 2",
+			"a == 1 && (x || y)",
+		},
+		{
+			"Should parenthesize a leaf with a conditional in it",
+			"3 OR 1",
+			"(n > 0 ? a : b) || a == 1",
+		},
+		{
+			"Should parenthesize a leaf that only looks like it has && in it",
+			"1 OR 4",
+			"a == 1 || (name == 'a && b')",
+		},
+		{
+			"Should not parenthesize a lone leaf",
+			"2",
+			"x || y",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			n, err := parse.Parse(c.logic)
+			assert.NoError(t, err)
+
+			actual, err := Export(n, render)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestExportErrors(t *testing.T) {
+	_, err := Export(nil, Ident)
+	assert.Equal(t, &parse.SerializeError{Reason: "nil tree"}, err)
+
+	_, err = Export(&parse.Op{Left: &parse.Leaf{Val: 1}, Val: "AND"}, Ident)
+	assert.Equal(t, &parse.SerializeError{Op: "AND", Reason: "nil right node"}, err)
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		desc     string
+		expr     string
+		expected string
+		err      *parse.ParseError
+	}{
+		{
+			"Should parse a single identifier",
+			"c1",
+			"1",
+			nil,
+		},
+		{
+			"Should read && before ||",
+			"c1 || c2 && c3",
+			"1 OR (2 AND 3)",
+			nil,
+		},
+		{
+			"Should read parentheses",
+			"(c1 || c2) && c3",
+			"1 OR 2 AND 3",
+			nil,
+		},
+		{
+			"Should read without spaces",
+			"c1&&(c2||c3)&&c4",
+			"1 AND (2 OR 3) AND 4",
+			nil,
+		},
+		{
+			"Should fail with negation",
+			"c1 && !c2",
+			"",
+			&parse.ParseError{
+				Position: 6,
+				Logic:    "c1 && !c2",
+				Reason:   "! is not supported, since condition logic has no negation",
+			},
+		},
+		{
+			"Should fail with an unknown identifier",
+			"c1 || status",
+			"",
+			&parse.ParseError{
+				Position: 6,
+				Logic:    "c1 || status",
+				Reason:   "status is not a condition",
+			},
+		},
+		{
+			"Should fail with a missing operand",
+			"c1 &&",
+			"",
+			&parse.ParseError{
+				Position: 5,
+				Logic:    "c1 &&",
+				Reason:   "unexpected end of expression",
+			},
+		},
+		{
+			"Should fail with a missing operator",
+			"c1 c2",
+			"",
+			&parse.ParseError{
+				Position: 3,
+				Logic:    "c1 c2",
+				Reason:   "unexpected c2",
+			},
+		},
+		{
+			"Should fail with unbalanced parentheses",
+			"(c1 || c2",
+			"",
+			&parse.ParseError{
+				Position: 9,
+				Logic:    "(c1 || c2",
+				Reason:   "unbalanced parenthesis",
+			},
+		},
+		{
+			"Should fail with a single &",
+			"c1 & c2",
+			"",
+			&parse.ParseError{
+				Position: 3,
+				Logic:    "c1 & c2",
+				Reason:   "unexpected &",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			n, err := Parse(c.expr, ResolveIdent)
+			if c.err != nil {
+				assert.Equal(t, c.err, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			var b strings.Builder
+			assert.NoError(t, n.Eval(&b))
+			assert.Equal(t, c.expected, b.String())
+		})
+	}
+}
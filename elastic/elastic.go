@@ -0,0 +1,71 @@
+// Package elastic renders condition logic as Elasticsearch bool queries. Each
+// leaf is turned into a query clause by a Resolver, AND groups become must
+// clauses and OR groups become should clauses with a minimum_should_match of
+// 1. Condition logic has no negation, so must_not is never used.
+package elastic
+
+import (
+	"github.com/skuid/balsa/parse"
+)
+
+// Resolver returns the query clause for a leaf. It can be anything that
+// encodes to JSON, like a map or a json.RawMessage.
+type Resolver func(leaf uint) (interface{}, error)
+
+// Options change how the query is built
+type Options struct {
+	// Filter puts AND groups made only of leaves into filter context, so
+	// Elasticsearch can cache them and doesn't score them
+	Filter bool
+}
+
+// Query will render the tree as a bool query, ready to be encoded as JSON. A
+// tree with a missing node or a bad operation fails before the resolver is
+// ever called.
+//	n, _ := parse.Parse("1 AND (2 OR 3)")
+//	elastic.Query(n, resolve, elastic.Options{})
+// Will yield {"bool": {"must": [c1, {"bool": {"should": [c2, c3], "minimum_should_match": 1}}]}}
+func Query(n parse.Node, resolve Resolver, opts Options) (map[string]interface{}, error) {
+	q, err := parse.Fold(n, func(l *parse.Leaf) (interface{}, error) {
+		return resolve(l.Val)
+	}, func(g *parse.Group, clauses []interface{}) (interface{}, error) {
+		return boolQuery(g, clauses, opts), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if l, ok := parse.Flatten(n).(*parse.Leaf); ok {
+		// A single leaf still needs to be a bool query
+		g := &parse.Group{Val: "AND", Nodes: []parse.Node{l}}
+		return boolQuery(g, []interface{}{q}, opts), nil
+	}
+
+	return q.(map[string]interface{}), nil
+}
+
+// boolQuery puts the clauses for the nodes of a group into must, should or
+// filter. Only an AND made of nothing but leaves goes into filter, since a
+// group under it might need scoring.
+func boolQuery(g *parse.Group, clauses []interface{}, opts Options) map[string]interface{} {
+	b := map[string]interface{}{}
+	switch {
+	case g.Val == "OR":
+		b["should"] = clauses
+		b["minimum_should_match"] = 1
+	case opts.Filter && onlyLeaves(g):
+		b["filter"] = clauses
+	default:
+		b["must"] = clauses
+	}
+	return map[string]interface{}{"bool": b}
+}
+
+func onlyLeaves(g *parse.Group) bool {
+	for _, c := range g.Nodes {
+		if _, ok := c.(*parse.Leaf); !ok {
+			return false
+		}
+	}
+	return true
+}
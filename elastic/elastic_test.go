@@ -0,0 +1,120 @@
+package elastic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/skuid/balsa/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+func term(leaf uint) (interface{}, error) {
+	return json.RawMessage(fmt.Sprintf(`{"term":{"c%d":true}}`, leaf)), nil
+}
+
+func TestQuery(t *testing.T) {
+	cases := []struct {
+		desc     string
+		logic    string
+		opts     Options
+		expected string
+	}{
+		{
+			"Should render a single leaf",
+			"1",
+			Options{},
+			`{"bool":{"must":[{"term":{"c1":true}}]}}`,
+		},
+		{
+			"Should flatten a chain of ANDs",
+			"1 AND 2 AND (3 AND 4)",
+			Options{},
+			`{"bool":{"must":[{"term":{"c1":true}},{"term":{"c2":true}},{"term":{"c3":true}},{"term":{"c4":true}}]}}`,
+		},
+		{
+			"Should render an OR as should",
+			"1 OR 2",
+			Options{},
+			`{"bool":{"minimum_should_match":1,"should":[{"term":{"c1":true}},{"term":{"c2":true}}]}}`,
+		},
+		{
+			"Should nest groups",
+			"1 AND (2 OR 3)",
+			Options{},
+			`{"bool":{"must":[{"term":{"c1":true}},{"bool":{"minimum_should_match":1,"should":[{"term":{"c2":true}},{"term":{"c3":true}}]}}]}}`,
+		},
+		{
+			"Should put pure ANDs into filter context",
+			"1 AND 2 OR (3 AND 4)",
+			Options{Filter: true},
+			`{"bool":{"minimum_should_match":1,"should":[{"bool":{"filter":[{"term":{"c1":true}},{"term":{"c2":true}}]}},{"bool":{"filter":[{"term":{"c3":true}},{"term":{"c4":true}}]}}]}}`,
+		},
+		{
+			"Should keep ANDs with groups in them as must",
+			"1 AND (2 OR 3)",
+			Options{Filter: true},
+			`{"bool":{"must":[{"term":{"c1":true}},{"bool":{"minimum_should_match":1,"should":[{"term":{"c2":true}},{"term":{"c3":true}}]}}]}}`,
+		},
+		{
+			"Should put a single leaf into filter context",
+			"1",
+			Options{Filter: true},
+			`{"bool":{"filter":[{"term":{"c1":true}}]}}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			n, err := parse.Parse(c.logic)
+			assert.NoError(t, err)
+
+			q, err := Query(n, term, c.opts)
+			assert.NoError(t, err)
+
+			actual, err := json.Marshal(q)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, string(actual))
+		})
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	failed := errors.New("no such condition")
+
+	cases := []struct {
+		desc     string
+		tree     parse.Node
+		resolve  Resolver
+		expected error
+	}{
+		{
+			"Should fail with a nil tree",
+			nil,
+			term,
+			&parse.SerializeError{Reason: "nil tree"},
+		},
+		{
+			"Should fail with a tree that can't be serialized",
+			&parse.Op{Left: &parse.Leaf{Val: 1}, Val: "AND"},
+			term,
+			&parse.SerializeError{Op: "AND", Reason: "nil right node"},
+		},
+		{
+			"Should pass on errors from the resolver",
+			&parse.Leaf{Val: 1},
+			func(leaf uint) (interface{}, error) {
+				return nil, failed
+			},
+			failed,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			_, err := Query(c.tree, c.resolve, Options{})
+			assert.Equal(t, c.expected, err)
+		})
+	}
+}
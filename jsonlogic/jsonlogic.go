@@ -0,0 +1,175 @@
+// Package jsonlogic converts condition logic to and from JSONLogic rules.
+// Rules are the values encoding/json works with, so a rule can be passed
+// straight to json.Marshal, and anything json.Unmarshal decodes into an
+// interface{} can be imported.
+package jsonlogic
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/skuid/balsa/parse"
+)
+
+// Template returns the rule for a leaf
+type Template func(leaf uint) (interface{}, error)
+
+// Matcher finds the leaf a rule stands for, returning false if the rule is not
+// a leaf
+type Matcher func(rule interface{}) (uint, bool)
+
+// Var is the default Template. It turns leaf 3 into {"var": "c3"}.
+func Var(leaf uint) (interface{}, error) {
+	return map[string]interface{}{"var": fmt.Sprintf("c%d", leaf)}, nil
+}
+
+// MatchVar is the default Matcher. It turns {"var": "c3"} into leaf 3.
+func MatchVar(rule interface{}) (uint, bool) {
+	m, ok := rule.(map[string]interface{})
+	if !ok || len(m) != 1 {
+		return 0, false
+	}
+
+	name, ok := m["var"].(string)
+	if !ok || !strings.HasPrefix(name, "c") {
+		return 0, false
+	}
+
+	v, err := strconv.ParseUint(name[1:], 10, 0)
+	if err != nil {
+		return 0, false
+	}
+	return uint(v), true
+}
+
+// Export will turn the tree into a JSONLogic rule. Chains of the same
+// operation are flattened into a single "and" or "or".
+//	n, _ := parse.Parse("1 AND (2 OR 3)")
+//	jsonlogic.Export(n, jsonlogic.Var)
+// Will yield {"and": [{"var": "c1"}, {"or": [{"var": "c2"}, {"var": "c3"}]}]}
+func Export(n parse.Node, leaf Template) (interface{}, error) {
+	return parse.Fold(n, func(l *parse.Leaf) (interface{}, error) {
+		return leaf(l.Val)
+	}, func(g *parse.Group, rules []interface{}) (interface{}, error) {
+		return map[string]interface{}{strings.ToLower(g.Val): rules}, nil
+	})
+}
+
+// Import will turn a JSONLogic rule into a tree. Any rule the matcher knows is
+// a leaf, and everything else has to be an "and" or an "or". The tree is
+// checked the same way Parse checks logic, and comes out the same as parsing
+// the logic it serializes to.
+//	var rule interface{}
+//	json.Unmarshal([]byte(`{"or": [{"var": "c1"}, {"var": "c2"}]}`), &rule)
+//	jsonlogic.Import(rule, jsonlogic.MatchVar)
+// Will yield 1 <- OR -> 2
+func Import(rule interface{}, match Matcher) (parse.Node, error) {
+	n, err := build(rule, match, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	if err := parse.Binarize(n).Eval(&b); err != nil {
+		return nil, err
+	}
+	return parse.Parse(b.String())
+}
+
+// Unmarshal will decode a JSONLogic rule and import it
+func Unmarshal(data []byte, match Matcher) (parse.Node, error) {
+	var rule interface{}
+	if err := json.Unmarshal(data, &rule); err != nil {
+		return nil, err
+	}
+	return Import(rule, match)
+}
+
+func build(rule interface{}, match Matcher, path string) (parse.Node, error) {
+	if v, ok := match(rule); ok {
+		return &parse.Leaf{Val: v}, nil
+	}
+
+	m, ok := rule.(map[string]interface{})
+	if !ok {
+		return nil, &Error{
+			Path:   pathString(path),
+			Reason: fmt.Sprintf("%v is not a rule", rule),
+		}
+	}
+
+	if len(m) != 1 {
+		return nil, &Error{
+			Path:   pathString(path),
+			Reason: fmt.Sprintf("rules need exactly one operator, found %d", len(m)),
+		}
+	}
+
+	for op, args := range m {
+		path = path + "/" + op
+
+		switch op {
+		case "and", "or":
+		case "!", "!!":
+			return nil, &Error{
+				Path:   path,
+				Reason: fmt.Sprintf("%s is not supported, since condition logic has no negation", op),
+			}
+		default:
+			return nil, &Error{
+				Path:   path,
+				Reason: fmt.Sprintf("%s is an unsupported operator", op),
+			}
+		}
+
+		list, ok := args.([]interface{})
+		if !ok {
+			return nil, &Error{
+				Path:   path,
+				Reason: fmt.Sprintf("%s needs a list of rules", op),
+			}
+		}
+
+		if len(list) == 0 {
+			return nil, &Error{
+				Path:   path,
+				Reason: fmt.Sprintf("%s needs at least one rule", op),
+			}
+		}
+
+		g := &parse.Group{
+			Val:   strings.ToUpper(op),
+			Nodes: make([]parse.Node, len(list)),
+		}
+		for i, r := range list {
+			c, err := build(r, match, fmt.Sprintf("%s/%d", path, i))
+			if err != nil {
+				return nil, err
+			}
+			g.Nodes[i] = c
+		}
+		return g, nil
+	}
+
+	return nil, nil
+}
+
+func pathString(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// Error holds information about a rule that could not be converted. Path is a
+// JSON pointer to the rule, like /and/1/or.
+type Error struct {
+	Path   string
+	Reason string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Could not convert rule at '%s'. Reason: %s", e.Path, e.Reason)
+}
@@ -0,0 +1,183 @@
+package jsonlogic
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/skuid/balsa/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExport(t *testing.T) {
+	cases := []struct {
+		desc     string
+		logic    string
+		expected string
+	}{
+		{
+			"Should export a single leaf",
+			"3",
+			`{"var":"c3"}`,
+		},
+		{
+			"Should flatten a chain of ANDs",
+			"1 AND 2 AND (3 AND 4)",
+			`{"and":[{"var":"c1"},{"var":"c2"},{"var":"c3"},{"var":"c4"}]}`,
+		},
+		{
+			"Should nest groups",
+			"1 OR 2 AND 3",
+			`{"and":[{"or":[{"var":"c1"},{"var":"c2"}]},{"var":"c3"}]}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			n, err := parse.Parse(c.logic)
+			assert.NoError(t, err)
+
+			rule, err := Export(n, Var)
+			assert.NoError(t, err)
+
+			actual, err := json.Marshal(rule)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, string(actual))
+
+			back, err := Import(rule, MatchVar)
+			assert.NoError(t, err)
+			assert.Equal(t, parse.Hash(n), parse.Hash(back))
+		})
+	}
+}
+
+func TestExportTemplate(t *testing.T) {
+	n, err := parse.Parse("1 OR 2")
+	assert.NoError(t, err)
+
+	rule, err := Export(n, func(leaf uint) (interface{}, error) {
+		return map[string]interface{}{"==": []interface{}{map[string]interface{}{"var": "status"}, leaf}}, nil
+	})
+	assert.NoError(t, err)
+
+	actual, err := json.Marshal(rule)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"or":[{"==":[{"var":"status"},1]},{"==":[{"var":"status"},2]}]}`, string(actual))
+}
+
+func TestUnmarshal(t *testing.T) {
+	cases := []struct {
+		desc     string
+		rule     string
+		expected string
+		err      error
+	}{
+		{
+			"Should import a single leaf",
+			`{"var":"c1"}`,
+			"1",
+			nil,
+		},
+		{
+			"Should import nested rules",
+			`{"and":[{"var":"c1"},{"or":[{"var":"c2"},{"var":"c3"}]},{"var":"c4"}]}`,
+			"1 AND (2 OR 3) AND 4",
+			nil,
+		},
+		{
+			"Should import a rule with one node",
+			`{"or":[{"and":[{"var":"c1"}]}]}`,
+			"1",
+			nil,
+		},
+		{
+			"Should fail with negation",
+			`{"and":[{"var":"c1"},{"!":{"var":"c2"}}]}`,
+			"",
+			&Error{
+				Path:   "/and/1/!",
+				Reason: "! is not supported, since condition logic has no negation",
+			},
+		},
+		{
+			"Should fail with an unsupported operator",
+			`{"or":[{"var":"c1"},{"==":[1,1]}]}`,
+			"",
+			&Error{
+				Path:   "/or/1/==",
+				Reason: "== is an unsupported operator",
+			},
+		},
+		{
+			"Should fail with a value that is not a rule",
+			`{"and":[{"var":"c1"},true]}`,
+			"",
+			&Error{
+				Path:   "/and/1",
+				Reason: "true is not a rule",
+			},
+		},
+		{
+			"Should fail with more than one operator",
+			`{"and":[{"var":"c1"}],"or":[{"var":"c2"}]}`,
+			"",
+			&Error{
+				Path:   "/",
+				Reason: "rules need exactly one operator, found 2",
+			},
+		},
+		{
+			"Should fail with an empty list",
+			`{"and":[]}`,
+			"",
+			&Error{
+				Path:   "/and",
+				Reason: "and needs at least one rule",
+			},
+		},
+		{
+			"Should fail without a list",
+			`{"or":{"var":"c1"}}`,
+			"",
+			&Error{
+				Path:   "/or",
+				Reason: "or needs a list of rules",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			n, err := Unmarshal([]byte(c.rule), MatchVar)
+			if c.err != nil {
+				assert.Equal(t, c.err, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			var b strings.Builder
+			assert.NoError(t, n.Eval(&b))
+			assert.Equal(t, c.expected, b.String())
+		})
+	}
+}
+
+func TestImportMatcher(t *testing.T) {
+	match := func(rule interface{}) (uint, bool) {
+		s, ok := rule.(string)
+		if !ok {
+			return 0, false
+		}
+		var v uint
+		_, err := fmt.Sscanf(s, "rule-%d", &v)
+		return v, err == nil
+	}
+
+	n, err := Import(map[string]interface{}{"and": []interface{}{"rule-1", "rule-2"}}, match)
+	assert.NoError(t, err)
+
+	var b strings.Builder
+	assert.NoError(t, n.Eval(&b))
+	assert.Equal(t, "1 AND 2", b.String())
+}
@@ -0,0 +1,48 @@
+// Package ldap renders condition logic as LDAP search filters, like
+// "(&(a=1)(|(b=2)(c=3)))"
+package ldap
+
+import (
+	"strings"
+
+	"github.com/skuid/balsa/parse"
+)
+
+// Renderer returns the filter for a leaf, like "(a=1)". Filters that are not
+// already in parentheses get wrapped in them.
+type Renderer func(leaf uint) (string, error)
+
+// Filter will write the tree as an LDAP filter. Chains of the same operation
+// are flattened, so "1 AND 2 AND 3" becomes a single (&...) with three
+// filters in it rather than one nested in another.
+//	n, _ := parse.Parse("1 AND (2 OR 3)")
+//	ldap.Filter(n, leaf)
+// Will yield "(&(a=1)(|(b=2)(c=3)))"
+func Filter(n parse.Node, leaf Renderer) (string, error) {
+	f, err := parse.Fold(n, func(l *parse.Leaf) (interface{}, error) {
+		s, err := leaf(l.Val)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+			return s, nil
+		}
+		return "(" + s + ")", nil
+	}, func(g *parse.Group, filters []interface{}) (interface{}, error) {
+		var b strings.Builder
+		if g.Val == "AND" {
+			b.WriteString("(&")
+		} else {
+			b.WriteString("(|")
+		}
+		for _, f := range filters {
+			b.WriteString(f.(string))
+		}
+		b.WriteString(")")
+		return b.String(), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return f.(string), nil
+}
@@ -0,0 +1,88 @@
+package ldap
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/skuid/balsa/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+var fields = map[uint]string{
+	1: "(a=1)",
+	2: "(b=2)",
+	3: "c=3",
+	4: "(d>=4)",
+}
+
+func field(leaf uint) (string, error) {
+	if f, ok := fields[leaf]; ok {
+		return f, nil
+	}
+	return "", errors.New("no such condition")
+}
+
+func TestFilter(t *testing.T) {
+	cases := []struct {
+		desc     string
+		logic    string
+		expected string
+		err      error
+	}{
+		{
+			"Should render a single leaf",
+			"1",
+			"(a=1)",
+			nil,
+		},
+		{
+			"Should wrap a leaf without parentheses",
+			"3",
+			"(c=3)",
+			nil,
+		},
+		{
+			"Should nest an OR under an AND",
+			"1 AND (2 OR 3)",
+			"(&(a=1)(|(b=2)(c=3)))",
+			nil,
+		},
+		{
+			"Should flatten a long AND chain",
+			"1 AND 2 AND 3 AND 4",
+			"(&(a=1)(b=2)(c=3)(d>=4))",
+			nil,
+		},
+		{
+			"Should keep left to right meaning",
+			"1 OR 2 AND 3 AND 4",
+			"(&(|(a=1)(b=2))(c=3)(d>=4))",
+			nil,
+		},
+		{
+			"Should pass on errors from the renderer",
+			"1 OR 5",
+			"",
+			errors.New("no such condition"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			n, err := parse.Parse(c.logic)
+			assert.NoError(t, err)
+
+			actual, err := Filter(n, field)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestFilterErrors(t *testing.T) {
+	_, err := Filter(nil, field)
+	assert.Equal(t, &parse.SerializeError{Reason: "nil tree"}, err)
+
+	_, err = Filter(&parse.Op{Left: &parse.Leaf{Val: 1}, Val: "NOR", Right: &parse.Leaf{Val: 2}}, field)
+	assert.Equal(t, &parse.SerializeError{Op: "NOR", Reason: "bad operation"}, err)
+}
@@ -0,0 +1,174 @@
+// Package lint looks for logic that parses fine but is probably not what the
+// user meant, like mixing AND and OR without parentheses or using the same
+// condition twice.
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/skuid/balsa/parse"
+)
+
+// Severity is how bad a diagnostic is
+type Severity int
+
+// Define the severities, from least to most severe
+const (
+	Info Severity = iota
+	Warning
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// Diagnostic is a single problem found by a rule, along with the path to the
+// node it was found at
+type Diagnostic struct {
+	Rule     string
+	Severity Severity
+	Path     parse.Path
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s at %v: %s (%s)", d.Severity, d.Path, d.Message, d.Rule)
+}
+
+// Reporter is handed to a Check to report each problem it finds
+type Reporter func(p parse.Path, message string)
+
+// Check looks for one kind of problem in a tree
+type Check func(n parse.Node, report Reporter)
+
+// LogicCheck looks for one kind of problem in a tree, knowing the logic it
+// was parsed from
+type LogicCheck func(logic string, n parse.Node, report Reporter)
+
+// Rule is a named Check that reports everything at the same severity. Rules
+// that need to see things Parse doesn't keep, like parentheses, also have a
+// CheckLogic that LintLogic uses instead. A rule can have either one or both,
+// and one with neither never reports anything. Copy a rule and change its
+// severity to configure it.
+//	strict := lint.MixedOperators
+//	strict.Severity = lint.Error
+type Rule struct {
+	Name       string
+	Severity   Severity
+	Check      Check
+	CheckLogic LogicCheck
+}
+
+// DefaultRules are the rules Lint will run if it is not given any
+func DefaultRules() []Rule {
+	return []Rule{
+		MixedOperators,
+		DuplicateLeaves,
+		Absorbed,
+		MaxDepth(5),
+	}
+}
+
+// Lint will run every rule over the tree and return everything they found, in
+// the order the rules were given. If no rules are given it will run the
+// DefaultRules. A tree can't tell which of its nodes were in parentheses, so
+// use LintLogic when the logic is at hand. Rules with only a CheckLogic are
+// handed the tree written back out with Eval, and skipped if that fails.
+//	n, _ := parse.Parse("1 OR 2 AND 3")
+//	lint.Lint(n)
+// Will warn that the logic is read as (1 OR 2) AND 3
+func Lint(n parse.Node, rules ...Rule) []Diagnostic {
+	if len(rules) == 0 {
+		rules = DefaultRules()
+	}
+
+	var diags []Diagnostic
+	for _, r := range rules {
+		switch {
+		case r.Check != nil:
+			r.Check(n, reporter(r, &diags))
+		case r.CheckLogic != nil:
+			if logic, ok := written(n); ok {
+				r.CheckLogic(logic, n, reporter(r, &diags))
+			}
+		}
+	}
+	return diags
+}
+
+// LintLogic will parse the logic and run every rule over the tree, the same
+// way Lint does. Rules with a CheckLogic get to see the logic itself, so
+// parentheses that Parse drops still count.
+//	lint.LintLogic("(1 OR 2) AND 3")
+// Will not warn, since the parentheses already say what the logic means
+func LintLogic(logic string, rules ...Rule) ([]Diagnostic, error) {
+	n, err := parse.Parse(logic)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rules) == 0 {
+		rules = DefaultRules()
+	}
+
+	var diags []Diagnostic
+	for _, r := range rules {
+		switch {
+		case r.CheckLogic != nil:
+			r.CheckLogic(logic, n, reporter(r, &diags))
+		case r.Check != nil:
+			r.Check(n, reporter(r, &diags))
+		}
+	}
+	return diags, nil
+}
+
+// written is the logic Eval writes for the tree, and false if it can't
+func written(n parse.Node) (string, bool) {
+	if n == nil {
+		return "", true
+	}
+
+	var b strings.Builder
+	if err := n.Eval(&b); err != nil {
+		return "", false
+	}
+	return b.String(), true
+}
+
+func reporter(r Rule, diags *[]Diagnostic) Reporter {
+	return func(p parse.Path, message string) {
+		*diags = append(*diags, Diagnostic{
+			Rule:     r.Name,
+			Severity: r.Severity,
+			Path:     p,
+			Message:  message,
+		})
+	}
+}
+
+// Worst will return the highest severity in the diagnostics, and false if
+// there aren't any
+func Worst(diags []Diagnostic) (Severity, bool) {
+	if len(diags) == 0 {
+		return Info, false
+	}
+
+	worst := diags[0].Severity
+	for _, d := range diags[1:] {
+		if d.Severity > worst {
+			worst = d.Severity
+		}
+	}
+	return worst, true
+}
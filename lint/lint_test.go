@@ -0,0 +1,215 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/skuid/balsa/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	cases := []struct {
+		desc     string
+		logic    string
+		expected []string
+	}{
+		{
+			"Should not find anything in clean logic",
+			"1 AND (2 OR 3)",
+			nil,
+		},
+		{
+			"Should run every default rule",
+			"1 OR 2 AND (1 OR 3)",
+			[]string{
+				"warning at /: OR and AND are mixed without parentheses, 1 OR 2 AND (1 OR 3) is read left to right as (1 OR 2) AND (1 OR 3), not with AND first like SQL would (mixed-operators)",
+				"warning at /1/0: condition 1 is already used at /0/0 (duplicate-leaves)",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			n, err := parse.Parse(c.logic)
+			assert.NoError(err)
+
+			var actual []string
+			for _, d := range Lint(n) {
+				actual = append(actual, d.String())
+			}
+			assert.Equal(c.expected, actual)
+		})
+	}
+}
+
+func TestLintRules(t *testing.T) {
+	assert := assert.New(t)
+	n, err := parse.Parse("1 OR 2 AND 1")
+	assert.NoError(err)
+
+	strict := MixedOperators
+	strict.Severity = Error
+
+	custom := Rule{
+		Name:     "no-leaf-two",
+		Severity: Info,
+		Check: func(n parse.Node, report Reporter) {
+			parse.WalkPaths(n, func(c parse.Node, p parse.Path) {
+				if l, ok := c.(*parse.Leaf); ok && l.Val == 2 {
+					report(p, "condition 2 is not allowed")
+				}
+			})
+		},
+	}
+
+	diags := Lint(n, strict, custom)
+	assert.Equal([]Diagnostic{
+		{
+			Rule:     "mixed-operators",
+			Severity: Error,
+			Path:     parse.Path{},
+			Message:  "OR and AND are mixed without parentheses, 1 OR 2 AND 1 is read left to right as (1 OR 2) AND 1, not with AND first like SQL would",
+		},
+		{
+			Rule:     "no-leaf-two",
+			Severity: Info,
+			Path:     parse.Path{0, 1},
+			Message:  "condition 2 is not allowed",
+		},
+	}, diags)
+
+	worst, ok := Worst(diags)
+	assert.True(ok)
+	assert.Equal(Error, worst)
+
+	_, ok = Worst(nil)
+	assert.False(ok)
+}
+
+func TestLintLogic(t *testing.T) {
+	cases := []struct {
+		desc     string
+		logic    string
+		expected []string
+	}{
+		{
+			"Should not warn about explicit parentheses",
+			"(1 OR 2) AND 3",
+			nil,
+		},
+		{
+			"Should not warn about nested explicit parentheses",
+			"((1 AND 2) OR 3) AND 4",
+			nil,
+		},
+		{
+			"Should still warn without parentheses",
+			"1 OR 2 AND 3",
+			[]string{
+				"warning at /: OR and AND are mixed without parentheses, 1 OR 2 AND 3 is read left to right as (1 OR 2) AND 3, not with AND first like SQL would (mixed-operators)",
+			},
+		},
+		{
+			"Should only warn where parentheses are missing",
+			"(1 OR 2) AND 3 OR 4",
+			[]string{
+				"warning at /: AND and OR are mixed without parentheses, 1 OR 2 AND 3 OR 4 is read left to right as (1 OR 2 AND 3) OR 4 (mixed-operators)",
+			},
+		},
+		{
+			"Should run rules without a CheckLogic on the tree",
+			"(1 OR 2) AND 1",
+			[]string{
+				"warning at /1: condition 1 is already used at /0/0 (duplicate-leaves)",
+				"warning at /0: (1 OR 2) does nothing since condition 1 at /1 already decides it (absorbed)",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			diags, err := LintLogic(c.logic)
+			assert.NoError(err)
+
+			var actual []string
+			for _, d := range diags {
+				actual = append(actual, d.String())
+			}
+			assert.Equal(c.expected, actual)
+		})
+	}
+}
+
+func TestLintLogicErrors(t *testing.T) {
+	_, err := LintLogic("1 AND")
+	assert.Equal(t, &parse.ParseError{Position: 2, Logic: "1 AND", Reason: "unexpected operation"}, err)
+}
+
+func TestCustomRules(t *testing.T) {
+	treeOnly := Rule{
+		Name: "tree-only",
+		Check: func(n parse.Node, report Reporter) {
+			report(parse.Path{}, "checked the tree")
+		},
+	}
+	logicOnly := Rule{
+		Name: "logic-only",
+		CheckLogic: func(logic string, n parse.Node, report Reporter) {
+			report(parse.Path{}, "checked "+logic)
+		},
+	}
+	neither := Rule{Name: "neither"}
+
+	n, err := parse.Parse("(1 OR 2) AND 3")
+	assert.NoError(t, err)
+
+	cases := []struct {
+		desc     string
+		lint     func() ([]Diagnostic, error)
+		expected []string
+	}{
+		{
+			"Should hand Lint rules the tree written back out",
+			func() ([]Diagnostic, error) {
+				return Lint(n, treeOnly, logicOnly, neither), nil
+			},
+			[]string{
+				"info at /: checked the tree (tree-only)",
+				"info at /: checked 1 OR 2 AND 3 (logic-only)",
+			},
+		},
+		{
+			"Should hand LintLogic rules the logic as written",
+			func() ([]Diagnostic, error) {
+				return LintLogic("(1 OR 2) AND 3", treeOnly, logicOnly, neither)
+			},
+			[]string{
+				"info at /: checked the tree (tree-only)",
+				"info at /: checked (1 OR 2) AND 3 (logic-only)",
+			},
+		},
+		{
+			"Should skip rules that can't write a broken tree out",
+			func() ([]Diagnostic, error) {
+				return Lint(&parse.Op{Left: &parse.Leaf{Val: 1}, Val: "AND"}, logicOnly), nil
+			},
+			nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			diags, err := c.lint()
+			assert.NoError(err)
+
+			var actual []string
+			for _, d := range diags {
+				actual = append(actual, d.String())
+			}
+			assert.Equal(c.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/skuid/balsa/parse"
+)
+
+// MixedOperators warns about AND and OR being mixed without parentheses.
+// Logic is read left to right, so "1 OR 2 AND 3" means "(1 OR 2) AND 3" and
+// not "1 OR (2 AND 3)" like it would with SQL precedence. Parentheses on the
+// left do not survive Parse, so Lint warns about how the tree is serialized,
+// while LintLogic leaves alone anything the logic already put in parentheses.
+var MixedOperators = Rule{
+	Name:       "mixed-operators",
+	Severity:   Warning,
+	Check:      checkMixed,
+	CheckLogic: checkMixedLogic,
+}
+
+// DuplicateLeaves warns about a condition that is used more than once, which
+// is how logic usually ends up contradicting itself after a few edits
+var DuplicateLeaves = Rule{
+	Name:     "duplicate-leaves",
+	Severity: Warning,
+	Check:    checkDuplicates,
+}
+
+// Absorbed finds groups that do nothing because a condition next to them
+// already decides the result, like the (1 OR 2) in "1 AND (1 OR 2)"
+var Absorbed = Rule{
+	Name:     "absorbed",
+	Severity: Warning,
+	Check:    checkAbsorbed,
+}
+
+// MaxDepth finds groups nested more than depth deep. A chain of the same
+// operation counts as one group, so "1 AND 2 AND 3" has a depth of 1 and
+// "1 AND (2 OR 3)" has a depth of 2.
+func MaxDepth(depth int) Rule {
+	return Rule{
+		Name:     "max-depth",
+		Severity: Info,
+		Check: func(n parse.Node, report Reporter) {
+			checkDepth(n, parse.Path{}, "", 0, depth, report)
+		},
+	}
+}
+
+func checkMixed(n parse.Node, report Reporter) {
+	mixed(n, map[string]bool{}, report)
+}
+
+func checkMixedLogic(logic string, n parse.Node, report Reporter) {
+	paths, err := parse.Parenthesized(logic)
+	if err != nil {
+		return
+	}
+
+	explicit := map[string]bool{}
+	for _, p := range paths {
+		explicit[p.String()] = true
+	}
+	mixed(n, explicit, report)
+}
+
+// mixed reports every operation whose first node is a different operation,
+// unless that node is at one of the explicit paths
+func mixed(n parse.Node, explicit map[string]bool, report Reporter) {
+	parse.WalkPaths(n, func(c parse.Node, p parse.Path) {
+		var first parse.Node
+		switch node := c.(type) {
+		case *parse.Op:
+			first = node.Left
+		case *parse.Group:
+			if len(node.Nodes) < 2 {
+				return
+			}
+			first = node.Nodes[0]
+		default:
+			return
+		}
+
+		op := opOf(c)
+		inner := opOf(first)
+		if inner == "" || inner == op || explicit[p.Child(0).String()] {
+			return
+		}
+
+		logic := eval(c)
+		reading := "(" + logic[:len(eval(first))] + ")" + logic[len(eval(first)):]
+		message := fmt.Sprintf("%s and %s are mixed without parentheses, %s is read left to right as %s", inner, op, logic, reading)
+		if op == "AND" {
+			message += ", not with AND first like SQL would"
+		}
+		report(p, message)
+	})
+}
+
+func checkDuplicates(n parse.Node, report Reporter) {
+	seen := map[uint]parse.Path{}
+	parse.WalkPaths(n, func(c parse.Node, p parse.Path) {
+		l, ok := c.(*parse.Leaf)
+		if !ok {
+			return
+		}
+		if at, ok := seen[l.Val]; ok {
+			report(p, fmt.Sprintf("condition %d is already used at %v", l.Val, at))
+			return
+		}
+		seen[l.Val] = p
+	})
+}
+
+type operand struct {
+	node parse.Node
+	path parse.Path
+}
+
+// operands collects everything joined by op, looking through any chain of
+// the same operation
+func operands(n parse.Node, p parse.Path, op string) []operand {
+	if opOf(n) != op {
+		return []operand{{n, p}}
+	}
+
+	var ops []operand
+	switch node := n.(type) {
+	case *parse.Op:
+		ops = append(ops, operands(node.Left, p.Child(0), op)...)
+		ops = append(ops, operands(node.Right, p.Child(1), op)...)
+	case *parse.Group:
+		for i, c := range node.Nodes {
+			ops = append(ops, operands(c, p.Child(i), op)...)
+		}
+	}
+	return ops
+}
+
+func checkAbsorbed(n parse.Node, report Reporter) {
+	parse.WalkPaths(n, func(c parse.Node, p parse.Path) {
+		op := opOf(c)
+		if op == "" || (len(p) > 0 && opOf(parse.At(n, p[:len(p)-1])) == op) {
+			// Only look at the top of each chain
+			return
+		}
+
+		ops := operands(c, p, op)
+		leaves := map[uint]parse.Path{}
+		for _, o := range ops {
+			if l, ok := o.node.(*parse.Leaf); ok {
+				if _, ok := leaves[l.Val]; !ok {
+					leaves[l.Val] = o.path
+				}
+			}
+		}
+
+		for _, o := range ops {
+			inner := opOf(o.node)
+			if inner == "" {
+				continue
+			}
+			for _, io := range operands(o.node, o.path, inner) {
+				l, ok := io.node.(*parse.Leaf)
+				if !ok {
+					continue
+				}
+				if at, ok := leaves[l.Val]; ok {
+					report(o.path, fmt.Sprintf("(%s) does nothing since condition %d at %v already decides it", eval(o.node), l.Val, at))
+					break
+				}
+			}
+		}
+	})
+}
+
+func checkDepth(n parse.Node, p parse.Path, parentOp string, level int, max int, report Reporter) {
+	op := opOf(n)
+	if op == "" {
+		return
+	}
+
+	if op != parentOp {
+		level++
+		if level == max+1 {
+			report(p, fmt.Sprintf("groups are nested more than %d deep", max))
+		}
+	}
+
+	switch node := n.(type) {
+	case *parse.Op:
+		checkDepth(node.Left, p.Child(0), op, level, max, report)
+		checkDepth(node.Right, p.Child(1), op, level, max, report)
+	case *parse.Group:
+		for i, c := range node.Nodes {
+			checkDepth(c, p.Child(i), op, level, max, report)
+		}
+	}
+}
+
+// opOf returns the operation of an Op or Group, and nothing for anything else
+func opOf(n parse.Node) string {
+	switch node := n.(type) {
+	case *parse.Op:
+		return node.Val
+	case *parse.Group:
+		return node.Val
+	}
+	return ""
+}
+
+func eval(n parse.Node) string {
+	var b strings.Builder
+	if err := n.Eval(&b); err != nil {
+		return fmt.Sprintf("%v", n)
+	}
+	return b.String()
+}
@@ -0,0 +1,129 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/skuid/balsa/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRules(t *testing.T) {
+	cases := []struct {
+		desc     string
+		rule     Rule
+		tree     parse.Node
+		expected []string
+	}{
+		{
+			"Should not mind the same operation without parentheses",
+			MixedOperators,
+			mustParse("1 AND 2 AND 3"),
+			nil,
+		},
+		{
+			"Should not mind mixed operations with parentheses",
+			MixedOperators,
+			mustParse("1 OR (2 AND 3)"),
+			nil,
+		},
+		{
+			"Should warn about OR before AND",
+			MixedOperators,
+			mustParse("1 OR 2 AND 3"),
+			[]string{
+				"OR and AND are mixed without parentheses, 1 OR 2 AND 3 is read left to right as (1 OR 2) AND 3, not with AND first like SQL would",
+			},
+		},
+		{
+			"Should warn about AND before OR",
+			MixedOperators,
+			mustParse("1 AND 2 OR 3"),
+			[]string{
+				"AND and OR are mixed without parentheses, 1 AND 2 OR 3 is read left to right as (1 AND 2) OR 3",
+			},
+		},
+		{
+			"Should warn about mixed operations in a group",
+			MixedOperators,
+			parse.Flatten(mustParse("1 AND 2 OR 3 OR 4")),
+			[]string{
+				"AND and OR are mixed without parentheses, 1 AND 2 OR 3 OR 4 is read left to right as (1 AND 2) OR 3 OR 4",
+			},
+		},
+		{
+			"Should find every duplicate",
+			DuplicateLeaves,
+			mustParse("1 AND (2 OR 1) AND 1"),
+			[]string{
+				"condition 1 is already used at /0/0",
+				"condition 1 is already used at /0/0",
+			},
+		},
+		{
+			"Should find a group absorbed by a leaf next to it",
+			Absorbed,
+			mustParse("1 AND 3 AND (2 OR 1)"),
+			[]string{
+				"(2 OR 1) does nothing since condition 1 at /0/0 already decides it",
+			},
+		},
+		{
+			"Should find an absorbed group in a flattened tree",
+			Absorbed,
+			parse.Flatten(mustParse("4 AND (1 OR (1 AND 2 AND 3))")),
+			[]string{
+				"(1 AND 2 AND 3) does nothing since condition 1 at /1/0 already decides it",
+			},
+		},
+		{
+			"Should not mind a group next to different conditions",
+			Absorbed,
+			mustParse("1 AND (2 OR 3)"),
+			nil,
+		},
+		{
+			"Should not mind a chain of the same operation",
+			MaxDepth(1),
+			mustParse("1 AND 2 AND 3 AND 4"),
+			nil,
+		},
+		{
+			"Should find groups nested too deep",
+			MaxDepth(2),
+			mustParse("1 AND (2 OR (3 AND (4 OR 5))) AND (6 OR (7 AND 8))"),
+			[]string{
+				"groups are nested more than 2 deep",
+				"groups are nested more than 2 deep",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var actual []string
+			for _, d := range Lint(c.tree, c.rule) {
+				actual = append(actual, d.Message)
+			}
+			assert.Equal(c.expected, actual)
+		})
+	}
+}
+
+func TestMaxDepthPath(t *testing.T) {
+	assert := assert.New(t)
+
+	diags := Lint(mustParse("1 AND (2 OR (3 AND 4))"), MaxDepth(2))
+	if assert.Len(diags, 1) {
+		assert.Equal(parse.Path{1, 1}, diags[0].Path)
+	}
+}
+
+func mustParse(logic string) parse.Node {
+	n, err := parse.Parse(logic)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
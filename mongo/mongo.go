@@ -0,0 +1,101 @@
+// Package mongo renders condition logic as MongoDB query documents. Each leaf
+// is turned into a condition document by a Resolver, and chains of the same
+// operation are flattened into a single $and or $or to keep the document
+// small. Condition logic has no negation, so $nor and $not are never used.
+// Broken trees fail with a SerializeError, the same as they would with Eval.
+package mongo
+
+import (
+	"github.com/skuid/balsa/parse"
+)
+
+// Resolver returns the condition document for a leaf, for example
+// map[string]interface{}{"status": "open"} or a D
+type Resolver func(leaf uint) (interface{}, error)
+
+// E is a single element of a D. It has the same fields as bson.E from the
+// MongoDB driver.
+type E struct {
+	Key   string
+	Value interface{}
+}
+
+// D is an ordered document. It has the same shape as bson.D from the MongoDB
+// driver, so copying each E into a bson.E gives a document the driver can
+// send.
+type D []E
+
+// Map will turn the D into an unordered document. Values that are D or []D
+// are turned into maps as well.
+func (d D) Map() map[string]interface{} {
+	m := make(map[string]interface{}, len(d))
+	for _, e := range d {
+		m[e.Key] = unorder(e.Value)
+	}
+	return m
+}
+
+func unorder(v interface{}) interface{} {
+	switch val := v.(type) {
+	case D:
+		return val.Map()
+	case []interface{}:
+		a := make([]interface{}, len(val))
+		for i, c := range val {
+			a[i] = unorder(c)
+		}
+		return a
+	}
+	return v
+}
+
+// Ordered will render the tree as an ordered query document.
+//	n, _ := parse.Parse("1 AND 2 AND (3 OR 4)")
+//	mongo.Ordered(n, resolve)
+// Will yield {$and: [c1, c2, {$or: [c3, c4]}]}
+func Ordered(n parse.Node, resolve Resolver) (D, error) {
+	v, err := query(n, resolve)
+	if err != nil {
+		return nil, err
+	}
+
+	if d, ok := v.(D); ok {
+		return d, nil
+	}
+
+	// A single leaf that didn't resolve to a D still needs to be a document
+	return D{{Key: "$and", Value: []interface{}{v}}}, nil
+}
+
+// Map will render the tree as a query document made of maps. Leaves can
+// resolve to either maps or D.
+//	n, _ := parse.Parse("1 OR 2")
+//	mongo.Map(n, resolve)
+// Will yield map[$or:[c1 c2]]
+func Map(n parse.Node, resolve Resolver) (map[string]interface{}, error) {
+	v, err := query(n, resolve)
+	if err != nil {
+		return nil, err
+	}
+
+	switch doc := unorder(v).(type) {
+	case map[string]interface{}:
+		return doc, nil
+	default:
+		return map[string]interface{}{"$and": []interface{}{doc}}, nil
+	}
+}
+
+// query folds the tree into a document, with each group becoming a single
+// $and or $or over the documents of its nodes
+func query(n parse.Node, resolve Resolver) (interface{}, error) {
+	return parse.Fold(n, func(l *parse.Leaf) (interface{}, error) {
+		return resolve(l.Val)
+	}, func(g *parse.Group, docs []interface{}) (interface{}, error) {
+		key := "$or"
+		if g.Val == "AND" {
+			key = "$and"
+		}
+		return D{{Key: key, Value: docs}}, nil
+	})
+}
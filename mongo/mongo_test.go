@@ -0,0 +1,154 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/skuid/balsa/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+func field(leaf uint) (interface{}, error) {
+	return D{{Key: fmt.Sprintf("c%d", leaf), Value: true}}, nil
+}
+
+func TestOrdered(t *testing.T) {
+	cases := []struct {
+		desc     string
+		logic    string
+		expected D
+	}{
+		{
+			"Should render a single leaf",
+			"1",
+			D{{Key: "c1", Value: true}},
+		},
+		{
+			"Should flatten a chain of ANDs",
+			"1 AND 2 AND (3 AND 4)",
+			D{{Key: "$and", Value: []interface{}{
+				D{{Key: "c1", Value: true}},
+				D{{Key: "c2", Value: true}},
+				D{{Key: "c3", Value: true}},
+				D{{Key: "c4", Value: true}},
+			}}},
+		},
+		{
+			"Should nest an OR under an AND",
+			"1 OR 2 AND 3",
+			D{{Key: "$and", Value: []interface{}{
+				D{{Key: "$or", Value: []interface{}{
+					D{{Key: "c1", Value: true}},
+					D{{Key: "c2", Value: true}},
+				}}},
+				D{{Key: "c3", Value: true}},
+			}}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			n, err := parse.Parse(c.logic)
+			assert.NoError(t, err)
+
+			actual, err := Ordered(n, field)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestMap(t *testing.T) {
+	cases := []struct {
+		desc     string
+		logic    string
+		resolve  Resolver
+		expected map[string]interface{}
+	}{
+		{
+			"Should turn ordered leaves into maps",
+			"1 AND (2 OR 3)",
+			field,
+			map[string]interface{}{"$and": []interface{}{
+				map[string]interface{}{"c1": true},
+				map[string]interface{}{"$or": []interface{}{
+					map[string]interface{}{"c2": true},
+					map[string]interface{}{"c3": true},
+				}},
+			}},
+		},
+		{
+			"Should keep map leaves",
+			"1 OR 2",
+			func(leaf uint) (interface{}, error) {
+				return map[string]interface{}{"n": map[string]interface{}{"$gt": leaf}}, nil
+			},
+			map[string]interface{}{"$or": []interface{}{
+				map[string]interface{}{"n": map[string]interface{}{"$gt": uint(1)}},
+				map[string]interface{}{"n": map[string]interface{}{"$gt": uint(2)}},
+			}},
+		},
+		{
+			"Should wrap a leaf that is not a document",
+			"1",
+			func(leaf uint) (interface{}, error) {
+				return "c1", nil
+			},
+			map[string]interface{}{"$and": []interface{}{"c1"}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			n, err := parse.Parse(c.logic)
+			assert.NoError(t, err)
+
+			actual, err := Map(n, c.resolve)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	failed := errors.New("no such condition")
+
+	cases := []struct {
+		desc     string
+		tree     parse.Node
+		resolve  Resolver
+		expected error
+	}{
+		{
+			"Should fail with a nil tree",
+			nil,
+			field,
+			&parse.SerializeError{Reason: "nil tree"},
+		},
+		{
+			"Should fail with a tree that can't be serialized",
+			&parse.Op{Left: &parse.Leaf{Val: 1}, Val: "XOR", Right: &parse.Leaf{Val: 2}},
+			field,
+			&parse.SerializeError{Op: "XOR", Reason: "bad operation"},
+		},
+		{
+			"Should pass on errors from the resolver",
+			&parse.Op{Left: &parse.Leaf{Val: 1}, Val: "AND", Right: &parse.Leaf{Val: 2}},
+			func(leaf uint) (interface{}, error) {
+				return nil, failed
+			},
+			failed,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			_, err := Ordered(c.tree, c.resolve)
+			assert.Equal(t, c.expected, err)
+
+			_, err = Map(c.tree, c.resolve)
+			assert.Equal(t, c.expected, err)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+// Package odata renders condition logic as OData $filter expressions, like
+// "a eq 1 and (b eq 2 or c eq 3)"
+package odata
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/skuid/balsa/parse"
+)
+
+// Renderer returns the expression for a leaf, like "a eq 1"
+type Renderer func(leaf uint) (string, error)
+
+// loose matches the and and or keywords in any case. It doesn't know about
+// string literals, so 'Smith and Sons' counts too.
+var loose = regexp.MustCompile(`(?i)\b(and|or)\b`)
+
+// Filter will write the tree as a $filter expression joined with and and or.
+// OData reads and before or, but rather than count on that, any group under
+// another one is put in parentheses, along with any leaf expression that has
+// an and or an or of its own.
+//	n, _ := parse.Parse("1 AND (2 OR 3)")
+//	odata.Filter(n, leaf)
+// Will yield "a eq 1 and (b eq 2 or c eq 3)"
+func Filter(n parse.Node, leaf Renderer) (string, error) {
+	f, err := parse.Fold(n, func(l *parse.Leaf) (interface{}, error) {
+		return leaf(l.Val)
+	}, func(g *parse.Group, filters []interface{}) (interface{}, error) {
+		parts := make([]string, len(filters))
+		for i, f := range filters {
+			parts[i] = f.(string)
+			if _, group := g.Nodes[i].(*parse.Group); group || loose.MatchString(parts[i]) {
+				parts[i] = "(" + parts[i] + ")"
+			}
+		}
+		return strings.Join(parts, " "+strings.ToLower(g.Val)+" "), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return f.(string), nil
+}
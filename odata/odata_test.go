@@ -0,0 +1,102 @@
+package odata
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/skuid/balsa/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+var fields = map[uint]string{
+	1: "a eq 1",
+	2: "b eq 2",
+	3: "c eq 3",
+	4: "d gt 4",
+	5: "e eq 5 or f eq 6",
+	6: "name eq 'Smith And Sons'",
+}
+
+func field(leaf uint) (string, error) {
+	if f, ok := fields[leaf]; ok {
+		return f, nil
+	}
+	return "", errors.New("no such condition")
+}
+
+func TestFilter(t *testing.T) {
+	cases := []struct {
+		desc     string
+		logic    string
+		expected string
+		err      error
+	}{
+		{
+			"Should render a single leaf",
+			"1",
+			"a eq 1",
+			nil,
+		},
+		{
+			"Should nest an OR under an AND",
+			"1 AND (2 OR 3)",
+			"a eq 1 and (b eq 2 or c eq 3)",
+			nil,
+		},
+		{
+			"Should keep left to right meaning",
+			"1 OR 2 AND 3",
+			"(a eq 1 or b eq 2) and c eq 3",
+			nil,
+		},
+		{
+			"Should flatten a chain of ORs",
+			"1 OR (2 OR 3) OR 4",
+			"a eq 1 or b eq 2 or c eq 3 or d gt 4",
+			nil,
+		},
+		{
+			"Should parenthesize a leaf with an or in it",
+			"1 AND 5",
+			"a eq 1 and (e eq 5 or f eq 6)",
+			nil,
+		},
+		{
+			"Should not parenthesize a lone leaf with an or in it",
+			"5",
+			"e eq 5 or f eq 6",
+			nil,
+		},
+		{
+			"Should parenthesize a leaf that only looks like it has an and in it",
+			"6 OR 1",
+			"(name eq 'Smith And Sons') or a eq 1",
+			nil,
+		},
+		{
+			"Should pass on errors from the renderer",
+			"1 AND 7",
+			"",
+			errors.New("no such condition"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			n, err := parse.Parse(c.logic)
+			assert.NoError(t, err)
+
+			actual, err := Filter(n, field)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestFilterErrors(t *testing.T) {
+	_, err := Filter(nil, field)
+	assert.Equal(t, &parse.SerializeError{Reason: "nil tree"}, err)
+
+	_, err = Filter(&parse.Op{Val: "OR", Right: &parse.Leaf{Val: 1}}, field)
+	assert.Equal(t, &parse.SerializeError{Op: "OR", Reason: "nil left node"}, err)
+}
@@ -0,0 +1,98 @@
+package parse
+
+import (
+	"golang.org/x/tools/container/intsets"
+)
+
+// MatchSparse will evaluate the tree for many rows at once. sets holds, for
+// each leaf, the rows where that leaf is true, and the result holds the rows
+// where the tree is true. Leaves missing from sets are false for every row.
+// None of the sets passed in are changed.
+//	n, _ := Parse("1 AND (2 OR 3)")
+//	rows := MatchSparse(n, sets)
+// If 1 is true for rows 0, 1 and 2, 2 for row 1 and 3 for rows 2 and 5, rows
+// will hold 1 and 2
+func MatchSparse(n Node, sets map[uint]*intsets.Sparse) *intsets.Sparse {
+	result := &intsets.Sparse{}
+
+	var op string
+	var children []Node
+
+	switch node := n.(type) {
+	case *Leaf:
+		if s, ok := sets[node.Val]; ok {
+			result.Copy(s)
+		}
+		return result
+	case *Op:
+		op = node.Val
+		children = []Node{node.Left, node.Right}
+	case *Group:
+		op = node.Val
+		children = node.Nodes
+	default:
+		return result
+	}
+
+	for i, c := range children {
+		s := MatchSparse(c, sets)
+		switch {
+		case i == 0:
+			result = s
+		case op == "AND":
+			result.IntersectionWith(s)
+		default:
+			result.UnionWith(s)
+		}
+		if op == "AND" && result.IsEmpty() {
+			break
+		}
+	}
+	return result
+}
+
+// MatchDense will evaluate the tree for many rows at once, the same way as
+// MatchSparse, but with dense bitmaps where row r is bit r%64 of word r/64.
+// Rows past the end of a bitmap are false, so the result is only as long as
+// it needs to be. None of the bitmaps passed in are changed.
+func MatchDense(n Node, bitmaps map[uint][]uint64) []uint64 {
+	var op string
+	var children []Node
+
+	switch node := n.(type) {
+	case *Leaf:
+		return append([]uint64{}, bitmaps[node.Val]...)
+	case *Op:
+		op = node.Val
+		children = []Node{node.Left, node.Right}
+	case *Group:
+		op = node.Val
+		children = node.Nodes
+	default:
+		return []uint64{}
+	}
+
+	var result []uint64
+	for i, c := range children {
+		b := MatchDense(c, bitmaps)
+		switch {
+		case i == 0:
+			result = b
+		case op == "AND":
+			if len(b) < len(result) {
+				result = result[:len(b)]
+			}
+			for w := range result {
+				result[w] &= b[w]
+			}
+		default:
+			if len(b) > len(result) {
+				result, b = b, result
+			}
+			for w := range b {
+				result[w] |= b[w]
+			}
+		}
+	}
+	return result
+}
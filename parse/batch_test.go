@@ -0,0 +1,139 @@
+package parse
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/tools/container/intsets"
+)
+
+func TestMatchSparse(t *testing.T) {
+	assert := assert.New(t)
+	n, err := Parse("1 AND (2 OR 3)")
+	assert.NoError(err)
+
+	sets := map[uint]*intsets.Sparse{
+		1: sparse(0, 1, 2, 1000000),
+		2: sparse(1, 1000000),
+		3: sparse(2, 5),
+	}
+
+	for _, tree := range []Node{n, Flatten(n)} {
+		rows := MatchSparse(tree, sets)
+		assert.Equal([]int{1, 2, 1000000}, rows.AppendTo(nil))
+	}
+
+	// The sets passed in should be untouched
+	assert.Equal([]int{0, 1, 2, 1000000}, sets[1].AppendTo(nil))
+	assert.Equal([]int{1, 1000000}, sets[2].AppendTo(nil))
+
+	assert.True(MatchSparse(&Leaf{9}, sets).IsEmpty())
+	assert.True(MatchSparse(nil, sets).IsEmpty())
+}
+
+func TestMatchDense(t *testing.T) {
+	assert := assert.New(t)
+	n, err := Parse("1 AND (2 OR 3)")
+	assert.NoError(err)
+
+	bitmaps := map[uint][]uint64{
+		1: {0x7, 0x1},
+		2: {0x2, 0x1},
+		3: {0x24},
+	}
+
+	for _, tree := range []Node{n, Flatten(n)} {
+		assert.Equal([]uint64{0x6, 0x1}, MatchDense(tree, bitmaps))
+	}
+
+	assert.Equal([]uint64{0x7, 0x1}, bitmaps[1])
+	assert.Equal([]uint64{0x2, 0x1}, bitmaps[2])
+
+	n, err = Parse("1 AND 3")
+	assert.NoError(err)
+	assert.Equal([]uint64{0x4}, MatchDense(n, bitmaps))
+}
+
+func TestMatchBatchRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	const rows = 200
+
+	for i := 0; i < 100; i++ {
+		n := randomTree(r, 5, 8)
+
+		sets := map[uint]*intsets.Sparse{}
+		bitmaps := map[uint][]uint64{}
+		values := map[uint][]bool{}
+		for leaf := uint(0); leaf < 8; leaf++ {
+			sets[leaf] = &intsets.Sparse{}
+			bitmaps[leaf] = make([]uint64, rows/64+1)
+			values[leaf] = make([]bool, rows)
+			for row := 0; row < rows; row++ {
+				if r.Intn(2) == 0 {
+					sets[leaf].Insert(row)
+					bitmaps[leaf][row/64] |= 1 << uint(row%64)
+					values[leaf][row] = true
+				}
+			}
+		}
+
+		s := MatchSparse(n, sets)
+		d := MatchDense(n, bitmaps)
+		for row := 0; row < rows; row++ {
+			expected := Match(n, func(v uint) bool { return values[v][row] })
+			assert.Equal(t, expected, s.Has(row), "MatchSparse %v row %d", n, row)
+			assert.Equal(t, expected, d[row/64]&(1<<uint(row%64)) != 0, "MatchDense %v row %d", n, row)
+		}
+	}
+}
+
+func sparse(vals ...int) *intsets.Sparse {
+	s := &intsets.Sparse{}
+	for _, v := range vals {
+		s.Insert(v)
+	}
+	return s
+}
+
+func BenchmarkMatchDense(b *testing.B) {
+	n := benchTree()
+	bitmaps := benchBitmaps()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MatchDense(n, bitmaps)
+	}
+}
+
+func BenchmarkProgramEvalRows(b *testing.B) {
+	p := mustCompile(benchTree())
+	bitmaps := benchBitmaps()
+	values := make([]bool, p.Leaves)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for row := 0; row < benchRows; row++ {
+			for leaf, bm := range bitmaps {
+				values[leaf] = bm[row/64]&(1<<uint(row%64)) != 0
+			}
+			p.Eval(values)
+		}
+	}
+}
+
+const benchRows = 1 << 20
+
+func benchBitmaps() map[uint][]uint64 {
+	r := rand.New(rand.NewSource(1))
+	bitmaps := map[uint][]uint64{}
+	WalkLeaves(benchTree(), func(n Node) Node {
+		bm := make([]uint64, benchRows/64)
+		for w := range bm {
+			bm[w] = r.Uint64()
+		}
+		bitmaps[n.(*Leaf).Val] = bm
+		return n
+	})
+	return bitmaps
+}
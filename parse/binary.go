@@ -0,0 +1,263 @@
+package parse
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+)
+
+// BinaryVersion is the version of the binary encoding written by
+// MarshalBinary. It is the first byte of every encoded tree.
+const BinaryVersion = 1
+
+// Define the bytes that start each node in the binary encoding
+const (
+	binaryLeaf byte = iota
+	binaryAnd
+	binaryOr
+	binaryAndGroup
+	binaryOrGroup
+)
+
+// MarshalBinary will encode the tree as a version byte followed by its nodes
+// in pre-order. A leaf is a byte followed by its value as a varint, an Op is a
+// single byte, and a Group is a byte followed by the number of nodes in it as
+// a varint. A nil tree is just the version byte.
+//	n, _ := Parse("1 AND (2 OR 3)")
+//	MarshalBinary(n)
+// Will yield 9 bytes, where "1 AND (2 OR 3)" takes 14
+func MarshalBinary(n Node) ([]byte, error) {
+	data := []byte{BinaryVersion}
+	if n == nil {
+		return data, nil
+	}
+
+	if err := n.Eval(ioutil.Discard); err != nil {
+		return nil, err
+	}
+
+	return appendBinary(data, n), nil
+}
+
+func appendBinary(data []byte, n Node) []byte {
+	var buf [binary.MaxVarintLen64]byte
+
+	switch node := n.(type) {
+	case *Leaf:
+		data = append(data, binaryLeaf)
+		return append(data, buf[:binary.PutUvarint(buf[:], uint64(node.Val))]...)
+	case *Op:
+		if node.Val == "AND" {
+			data = append(data, binaryAnd)
+		} else {
+			data = append(data, binaryOr)
+		}
+		data = appendBinary(data, node.Left)
+		return appendBinary(data, node.Right)
+	case *Group:
+		if len(node.Nodes) == 1 {
+			return appendBinary(data, node.Nodes[0])
+		}
+		if node.Val == "AND" {
+			data = append(data, binaryAndGroup)
+		} else {
+			data = append(data, binaryOrGroup)
+		}
+		data = append(data, buf[:binary.PutUvarint(buf[:], uint64(len(node.Nodes)))]...)
+		for _, c := range node.Nodes {
+			data = appendBinary(data, c)
+		}
+	}
+	return data
+}
+
+// UnmarshalBinary will decode a tree encoded by MarshalBinary. Everything is
+// checked along the way, so a tree that comes back is always one Eval can
+// serialize.
+func UnmarshalBinary(data []byte) (Node, error) {
+	if len(data) == 0 {
+		return nil, &DecodeError{
+			Offset: 0,
+			Reason: "no data",
+		}
+	}
+
+	if data[0] != BinaryVersion {
+		return nil, &DecodeError{
+			Offset: 0,
+			Reason: fmt.Sprintf("unsupported version %d", data[0]),
+		}
+	}
+
+	if len(data) == 1 {
+		return nil, nil
+	}
+
+	// parents holds every Op and Group still waiting on nodes, with the
+	// number of nodes each one has left to take
+	type parent struct {
+		node Node
+		left int
+	}
+	var parents []parent
+	var root Node
+
+	i := 1
+	for i < len(data) {
+		start := i
+		kind := data[i]
+		i++
+
+		var n Node
+		var wants int
+		switch kind {
+		case binaryLeaf:
+			v, size := binary.Uvarint(data[i:])
+			if size <= 0 || v > uint64(^uint(0)) {
+				return nil, &DecodeError{
+					Offset: i,
+					Reason: "bad leaf value",
+				}
+			}
+			i += size
+			n = &Leaf{uint(v)}
+		case binaryAnd, binaryOr:
+			op := "AND"
+			if kind == binaryOr {
+				op = "OR"
+			}
+			n = &Op{Val: op}
+			wants = 2
+		case binaryAndGroup, binaryOrGroup:
+			op := "AND"
+			if kind == binaryOrGroup {
+				op = "OR"
+			}
+			count, size := binary.Uvarint(data[i:])
+			// Every node takes at least one byte, which keeps a bad count
+			// from allocating more than the data could ever hold
+			if size <= 0 || count < 2 || count > uint64(len(data)-i-size) {
+				return nil, &DecodeError{
+					Offset: i,
+					Reason: "bad group size",
+				}
+			}
+			i += size
+			n = &Group{Val: op, Nodes: make([]Node, 0, count)}
+			wants = int(count)
+		default:
+			return nil, &DecodeError{
+				Offset: start,
+				Reason: fmt.Sprintf("unknown node type %d", kind),
+			}
+		}
+
+		if root == nil {
+			root = n
+		} else if len(parents) == 0 {
+			return nil, &DecodeError{
+				Offset: start,
+				Reason: "unexpected data after the tree",
+			}
+		} else {
+			p := &parents[len(parents)-1]
+			switch node := p.node.(type) {
+			case *Op:
+				if p.left == 2 {
+					node.Left = n
+				} else {
+					node.Right = n
+				}
+			case *Group:
+				node.Nodes = append(node.Nodes, n)
+			}
+			p.left--
+			if p.left == 0 {
+				parents = parents[:len(parents)-1]
+			}
+		}
+
+		if wants > 0 {
+			parents = append(parents, parent{n, wants})
+		}
+	}
+
+	if len(parents) > 0 {
+		return nil, &DecodeError{
+			Offset: len(data),
+			Reason: "unexpected end of data",
+		}
+	}
+
+	return root, nil
+}
+
+// MarshalBinary will encode the leaf with MarshalBinary
+func (l *Leaf) MarshalBinary() ([]byte, error) {
+	return MarshalBinary(l)
+}
+
+// MarshalBinary will encode the tree under the operation with MarshalBinary
+func (o *Op) MarshalBinary() ([]byte, error) {
+	return MarshalBinary(o)
+}
+
+// MarshalBinary will encode the tree under the group with MarshalBinary
+func (g *Group) MarshalBinary() ([]byte, error) {
+	return MarshalBinary(g)
+}
+
+// UnmarshalBinary will decode a tree with UnmarshalBinary, which has to be a
+// single leaf
+func (l *Leaf) UnmarshalBinary(data []byte) error {
+	n, err := UnmarshalBinary(data)
+	if err != nil {
+		return err
+	}
+	leaf, ok := n.(*Leaf)
+	if !ok {
+		return &DecodeError{Reason: fmt.Sprintf("expected a leaf, found %T", n)}
+	}
+	*l = *leaf
+	return nil
+}
+
+// UnmarshalBinary will decode a tree with UnmarshalBinary, which has to start
+// with an operation
+func (o *Op) UnmarshalBinary(data []byte) error {
+	n, err := UnmarshalBinary(data)
+	if err != nil {
+		return err
+	}
+	op, ok := n.(*Op)
+	if !ok {
+		return &DecodeError{Reason: fmt.Sprintf("expected an operation, found %T", n)}
+	}
+	*o = *op
+	return nil
+}
+
+// UnmarshalBinary will decode a tree with UnmarshalBinary, which has to start
+// with a group
+func (g *Group) UnmarshalBinary(data []byte) error {
+	n, err := UnmarshalBinary(data)
+	if err != nil {
+		return err
+	}
+	group, ok := n.(*Group)
+	if !ok {
+		return &DecodeError{Reason: fmt.Sprintf("expected a group, found %T", n)}
+	}
+	*g = *group
+	return nil
+}
+
+// DecodeError holds information about binary data that could not be decoded
+type DecodeError struct {
+	Offset int
+	Reason string
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("Could not decode tree at offset %d. Reason: %s", e.Offset, e.Reason)
+}
@@ -0,0 +1,179 @@
+package parse
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	cases := []struct {
+		desc     string
+		tree     Node
+		expected []byte
+	}{
+		{
+			"Should encode a nil tree",
+			nil,
+			[]byte{1},
+		},
+		{
+			"Should encode a leaf",
+			&Leaf{300},
+			[]byte{1, 0, 0xac, 0x02},
+		},
+		{
+			"Should encode operations in pre-order",
+			mustSexp("(AND 1 (OR 2 3))"),
+			[]byte{1, 1, 0, 1, 2, 0, 2, 0, 3},
+		},
+		{
+			"Should encode groups with their size",
+			mustSexp("[OR 1 [AND 2 3 4]]"),
+			[]byte{1, 4, 2, 0, 1, 3, 3, 0, 2, 0, 3, 0, 4},
+		},
+		{
+			"Should encode a group with one node as the node",
+			mustSexp("(AND [OR 1] 2)"),
+			[]byte{1, 1, 0, 1, 0, 2},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			data, err := MarshalBinary(c.tree)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, data)
+
+			n, err := UnmarshalBinary(data)
+			assert.NoError(t, err)
+			assert.Equal(t, Hash(c.tree), Hash(n))
+		})
+	}
+}
+
+func TestMarshalBinaryErrors(t *testing.T) {
+	_, err := MarshalBinary(mustSexp("(XOR 1 2)"))
+	assert.Equal(t, &SerializeError{Op: "XOR", Reason: "bad operation"}, err)
+
+	_, err = MarshalBinary(mustSexp("(AND 1 nil)"))
+	assert.Equal(t, &SerializeError{Op: "AND", Reason: "nil right node"}, err)
+}
+
+func TestUnmarshalBinaryErrors(t *testing.T) {
+	cases := []struct {
+		desc string
+		data []byte
+		err  *DecodeError
+	}{
+		{
+			"Should fail without data",
+			nil,
+			&DecodeError{Offset: 0, Reason: "no data"},
+		},
+		{
+			"Should fail with another version",
+			[]byte{2, 0, 1},
+			&DecodeError{Offset: 0, Reason: "unsupported version 2"},
+		},
+		{
+			"Should fail with an unknown node",
+			[]byte{1, 1, 0, 1, 9},
+			&DecodeError{Offset: 4, Reason: "unknown node type 9"},
+		},
+		{
+			"Should fail with a cut off leaf",
+			[]byte{1, 0, 0xac},
+			&DecodeError{Offset: 2, Reason: "bad leaf value"},
+		},
+		{
+			"Should fail with a missing node",
+			[]byte{1, 2, 0, 1},
+			&DecodeError{Offset: 4, Reason: "unexpected end of data"},
+		},
+		{
+			"Should fail with data after the tree",
+			[]byte{1, 0, 1, 0, 2},
+			&DecodeError{Offset: 3, Reason: "unexpected data after the tree"},
+		},
+		{
+			"Should fail with a group that is too big",
+			[]byte{1, 3, 0xff, 0xff, 0xff, 0xff, 0x0f, 0, 1},
+			&DecodeError{Offset: 2, Reason: "bad group size"},
+		},
+		{
+			"Should fail with a group that is too small",
+			[]byte{1, 3, 1, 0, 1},
+			&DecodeError{Offset: 2, Reason: "bad group size"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			n, err := UnmarshalBinary(c.data)
+			assert.Equal(t, c.err, err)
+			assert.Nil(t, n)
+		})
+	}
+}
+
+func TestNodeBinary(t *testing.T) {
+	data, err := mustSexp("(OR 1 2)").(*Op).MarshalBinary()
+	assert.NoError(t, err)
+
+	var o Op
+	assert.NoError(t, o.UnmarshalBinary(data))
+	assert.Equal(t, "(OR 1 2)", Sexp(&o))
+
+	var l Leaf
+	assert.Equal(t, &DecodeError{Reason: "expected a leaf, found *parse.Op"}, l.UnmarshalBinary(data))
+
+	var g Group
+	assert.Equal(t, &DecodeError{Reason: "expected a group, found *parse.Op"}, g.UnmarshalBinary(data))
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(6))
+	for i := 0; i < 200; i++ {
+		n := randomTree(r, 6, 300)
+		if i%2 == 0 {
+			n = Flatten(n)
+		}
+
+		data, err := MarshalBinary(n)
+		assert.NoError(t, err)
+
+		back, err := UnmarshalBinary(data)
+		assert.NoError(t, err)
+		assert.True(t, Equal(n, back), Sexp(n))
+
+		// Dropping the last byte always leaves the tree unfinished
+		_, err = UnmarshalBinary(data[:len(data)-1])
+		assert.Error(t, err)
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	var s strings.Builder
+	benchTree().Eval(&s)
+	logic := s.String()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Parse(logic)
+	}
+}
+
+func BenchmarkUnmarshalBinary(b *testing.B) {
+	data, err := MarshalBinary(benchTree())
+	if err != nil {
+		panic(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		UnmarshalBinary(data)
+	}
+}
@@ -0,0 +1,127 @@
+package parse
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Canonicalize will flatten the tree and sort the nodes of every group, so
+// any two trees that only differ by the order of their nodes or by how they
+// are parenthesized end up the same. Leaves sort before groups, leaves are
+// sorted by value and groups are sorted by their operation and then by their
+// nodes.
+//	a, _ := Parse("(2 AND 1) AND (4 OR 3)")
+//	b, _ := Parse("(3 OR 4) AND 1 AND 2")
+// Both canonicalize to AND[1 2 OR[3 4]]
+func Canonicalize(n Node) Node {
+	return canonical(Flatten(n))
+}
+
+func canonical(n Node) Node {
+	g, ok := n.(*Group)
+	if !ok {
+		return n
+	}
+
+	nodes := make([]Node, len(g.Nodes))
+	for i, c := range g.Nodes {
+		nodes[i] = canonical(c)
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return compare(nodes[i], nodes[j]) < 0
+	})
+
+	return &Group{
+		Val:   g.Val,
+		Nodes: nodes,
+	}
+}
+
+// compare orders two canonical nodes, returning -1, 0 or 1
+func compare(a Node, b Node) int {
+	switch x := a.(type) {
+	case *Leaf:
+		y, ok := b.(*Leaf)
+		if !ok {
+			return -1
+		}
+		if x.Val < y.Val {
+			return -1
+		}
+		if x.Val > y.Val {
+			return 1
+		}
+		return 0
+	case *Group:
+		y, ok := b.(*Group)
+		if !ok {
+			return 1
+		}
+		if c := strings.Compare(x.Val, y.Val); c != 0 {
+			return c
+		}
+		if len(x.Nodes) != len(y.Nodes) {
+			if len(x.Nodes) < len(y.Nodes) {
+				return -1
+			}
+			return 1
+		}
+		for i := range x.Nodes {
+			if c := compare(x.Nodes[i], y.Nodes[i]); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+	return 0
+}
+
+// CanonicalString will serialize the canonical form of the tree, which makes
+// it a good key for anything that should not care about the order of
+// conditions.
+//	CanonicalString(Parse("2 AND 1 OR 3")) == "3 OR (1 AND 2)"
+func CanonicalString(n Node) (string, error) {
+	c := Canonicalize(n)
+	if c == nil {
+		return "", nil
+	}
+
+	var b strings.Builder
+	if err := c.Eval(&b); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Hash will hash the canonical form of the tree. Trees that are the same up
+// to the order of their nodes and how they are parenthesized have the same
+// hash.
+func Hash(n Node) uint64 {
+	h := fnv.New64a()
+	writeHash(h, Canonicalize(n))
+	return h.Sum64()
+}
+
+func writeHash(h io.Writer, n Node) {
+	var buf [binary.MaxVarintLen64]byte
+
+	switch node := n.(type) {
+	case *Leaf:
+		h.Write([]byte{'L'})
+		h.Write(buf[:binary.PutUvarint(buf[:], uint64(node.Val))])
+	case *Group:
+		h.Write([]byte{'G'})
+		h.Write(buf[:binary.PutUvarint(buf[:], uint64(len(node.Val)))])
+		h.Write([]byte(node.Val))
+		h.Write(buf[:binary.PutUvarint(buf[:], uint64(len(node.Nodes)))])
+		for _, c := range node.Nodes {
+			writeHash(h, c)
+		}
+	default:
+		h.Write([]byte{'N'})
+	}
+}
@@ -0,0 +1,153 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalize(t *testing.T) {
+	cases := []struct {
+		desc     string
+		logic    string
+		expected Node
+	}{
+		{
+			"Should leave a single leaf alone",
+			"3",
+			&Leaf{3},
+		},
+		{
+			"Should sort leaves",
+			"3 AND 1 AND 2",
+			&Group{
+				Val:   "AND",
+				Nodes: []Node{&Leaf{1}, &Leaf{2}, &Leaf{3}},
+			},
+		},
+		{
+			"Should sort leaves before groups and groups by operation",
+			"(5 OR 4) AND (3 AND 2 OR 1) AND 6",
+			&Group{
+				Val: "AND",
+				Nodes: []Node{
+					&Leaf{6},
+					&Group{
+						Val: "OR",
+						Nodes: []Node{
+							&Leaf{1},
+							&Group{
+								Val:   "AND",
+								Nodes: []Node{&Leaf{2}, &Leaf{3}},
+							},
+						},
+					},
+					&Group{
+						Val:   "OR",
+						Nodes: []Node{&Leaf{4}, &Leaf{5}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			n, err := Parse(c.logic)
+			assert.NoError(err)
+
+			deepEql(assert, c.expected, Canonicalize(n))
+		})
+	}
+}
+
+func TestHash(t *testing.T) {
+	cases := []struct {
+		desc  string
+		a     string
+		b     string
+		equal bool
+	}{
+		{
+			"Should match the same logic",
+			"1 AND 2",
+			"1 AND 2",
+			true,
+		},
+		{
+			"Should match commuted logic",
+			"1 AND 2",
+			"2 AND 1",
+			true,
+		},
+		{
+			"Should match differently parenthesized logic",
+			"(2 AND 1) AND (4 OR 3)",
+			"(3 OR 4) AND 1 AND 2",
+			true,
+		},
+		{
+			"Should match deeply commuted logic",
+			"1 OR (5 AND (7 OR 8)) AND (3 AND 2 OR (56 AND 1000) OR 4)",
+			"(4 OR (1000 AND 56) OR (2 AND 3)) AND ((8 OR 7) AND 5 OR 1)",
+			true,
+		},
+		{
+			"Should not match different operations",
+			"1 AND 2",
+			"1 OR 2",
+			false,
+		},
+		{
+			"Should not match different leaves",
+			"1 AND 2",
+			"1 AND 3",
+			false,
+		},
+		{
+			"Should not match different groupings",
+			"1 OR 2 AND 3",
+			"1 OR (2 AND 3)",
+			false,
+		},
+		{
+			"Should not match repeated leaves",
+			"1 AND 1",
+			"1",
+			false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			a, err := Parse(c.a)
+			assert.NoError(err)
+			b, err := Parse(c.b)
+			assert.NoError(err)
+
+			assert.Equal(c.equal, Hash(a) == Hash(b))
+
+			as, err := CanonicalString(a)
+			assert.NoError(err)
+			bs, err := CanonicalString(b)
+			assert.NoError(err)
+			assert.Equal(c.equal, as == bs, "Expected canonical strings %q and %q to match: %v", as, bs, c.equal)
+		})
+	}
+}
+
+func TestCanonicalString(t *testing.T) {
+	assert := assert.New(t)
+	n, err := Parse("2 AND 1 OR 3")
+	assert.NoError(err)
+
+	s, err := CanonicalString(n)
+	assert.NoError(err)
+	assert.Equal("3 OR (1 AND 2)", s)
+
+	s, err = CanonicalString(nil)
+	assert.NoError(err)
+	assert.Equal("", s)
+}
@@ -0,0 +1,180 @@
+package parse
+
+import (
+	"fmt"
+	"math"
+)
+
+type opcode uint8
+
+// Define the instructions a Program can run
+const (
+	// load sets the result to the value of the leaf in arg
+	load opcode = iota
+	// jumpFalse jumps to arg if the result is false
+	jumpFalse
+	// jumpTrue jumps to arg if the result is true
+	jumpTrue
+)
+
+type instruction struct {
+	op  opcode
+	arg uint32
+}
+
+// Program is a tree compiled down to a flat list of instructions, which is
+// much cheaper to evaluate over and over than walking the tree. It evaluates
+// the same way Match does, left to right and stopping as soon as the result is
+// known.
+type Program struct {
+	code []instruction
+	// Leaves is the number of leaf values the program expects, which is one
+	// more than the highest leaf
+	Leaves int
+}
+
+// Compile will turn a tree into a Program.
+//	n, _ := Parse("1 AND (2 OR 3)")
+//	p, _ := Compile(n)
+//	p.Eval([]bool{false, true, false, true})
+// Will return true
+func Compile(n Node) (*Program, error) {
+	p := &Program{}
+	if err := p.compile(n); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Program) compile(n Node) error {
+	var op string
+	var children []Node
+
+	switch node := n.(type) {
+	case *Leaf:
+		if uint64(node.Val) > math.MaxUint32 {
+			return &CompileError{
+				Op:     fmt.Sprintf("%d", node.Val),
+				Reason: "leaf is too big",
+			}
+		}
+		if int(node.Val) >= p.Leaves {
+			p.Leaves = int(node.Val) + 1
+		}
+		p.code = append(p.code, instruction{load, uint32(node.Val)})
+		return nil
+	case *Op:
+		op = node.Val
+		children = []Node{node.Left, node.Right}
+	case *Group:
+		op = node.Val
+		children = node.Nodes
+		if len(children) == 0 {
+			return &CompileError{
+				Op:     op,
+				Reason: "empty group",
+			}
+		}
+	default:
+		return &CompileError{
+			Reason: "nil node",
+		}
+	}
+
+	if !validOp(op) && len(children) > 1 {
+		return &CompileError{
+			Op:     op,
+			Reason: "bad operation",
+		}
+	}
+
+	jump := jumpFalse
+	if op == "OR" {
+		jump = jumpTrue
+	}
+
+	// Every child but the last jumps to the end as soon as it decides the
+	// result, and we patch in where the end is once we know
+	var jumps []int
+	for i, c := range children {
+		if c == nil {
+			return &CompileError{
+				Op:     op,
+				Reason: fmt.Sprintf("nil node at %d", i),
+			}
+		}
+		if err := p.compile(c); err != nil {
+			return err
+		}
+		if i < len(children)-1 {
+			jumps = append(jumps, len(p.code))
+			p.code = append(p.code, instruction{jump, 0})
+		}
+	}
+
+	for _, j := range jumps {
+		p.code[j].arg = uint32(len(p.code))
+	}
+	return nil
+}
+
+// Eval will run the program with the value of each leaf at its index in
+// values. Leaves past the end of values are false.
+func (p *Program) Eval(values []bool) bool {
+	result := false
+	for pc := 0; pc < len(p.code); pc++ {
+		in := p.code[pc]
+		switch in.op {
+		case load:
+			result = int(in.arg) < len(values) && values[in.arg]
+		case jumpFalse:
+			if !result {
+				pc = int(in.arg) - 1
+			}
+		case jumpTrue:
+			if result {
+				pc = int(in.arg) - 1
+			}
+		}
+	}
+	return result
+}
+
+// EvalBits will run the program with the value of each leaf held in a bitset,
+// where leaf v is bit v%64 of bits[v/64]. Leaves past the end of bits are
+// false.
+func (p *Program) EvalBits(bits []uint64) bool {
+	result := false
+	for pc := 0; pc < len(p.code); pc++ {
+		in := p.code[pc]
+		switch in.op {
+		case load:
+			w := int(in.arg / 64)
+			result = w < len(bits) && bits[w]&(1<<(in.arg%64)) != 0
+		case jumpFalse:
+			if !result {
+				pc = int(in.arg) - 1
+			}
+		case jumpTrue:
+			if result {
+				pc = int(in.arg) - 1
+			}
+		}
+	}
+	return result
+}
+
+// Len is the number of instructions in the program
+func (p *Program) Len() int {
+	return len(p.code)
+}
+
+// CompileError holds information about a tree that could not be compiled
+type CompileError struct {
+	Op     string
+	Reason string
+}
+
+func (e *CompileError) Error() string {
+	return fmt.Sprintf("Could not compile operation '%s'. Reason: %s", e.Op, e.Reason)
+}
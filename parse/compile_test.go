@@ -0,0 +1,195 @@
+package parse
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompile(t *testing.T) {
+	cases := []struct {
+		desc   string
+		logic  string
+		values []bool
+		result bool
+	}{
+		{
+			"Should run a single leaf",
+			"1",
+			[]bool{false, true},
+			true,
+		},
+		{
+			"Should read left to right",
+			"1 OR 2 AND 3",
+			[]bool{false, true, false, false},
+			false,
+		},
+		{
+			"Should run parens",
+			"1 AND (2 OR 3)",
+			[]bool{false, true, false, true},
+			true,
+		},
+		{
+			"Should treat leaves past the end as false",
+			"1 AND 7 OR 0",
+			[]bool{true, true},
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			n, err := Parse(c.logic)
+			assert.NoError(err)
+
+			p, err := Compile(n)
+			assert.NoError(err)
+			assert.Equal(c.result, p.Eval(c.values))
+
+			var bits uint64
+			for i, v := range c.values {
+				if v {
+					bits |= 1 << uint(i)
+				}
+			}
+			assert.Equal(c.result, p.EvalBits([]uint64{bits}))
+		})
+	}
+}
+
+func TestCompileRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		n := randomTree(r, 5, 70)
+		p, err := Compile(n)
+		assert.NoError(t, err)
+
+		values := make([]bool, p.Leaves)
+		bits := make([]uint64, 2)
+		for j := 0; j < 20; j++ {
+			for k := range values {
+				values[k] = r.Intn(2) == 0
+				if values[k] {
+					bits[k/64] |= 1 << uint(k%64)
+				} else {
+					bits[k/64] &^= 1 << uint(k%64)
+				}
+			}
+			expected := Match(n, func(v uint) bool { return values[v] })
+			assert.Equal(t, expected, p.Eval(values), "Eval %v with %v", n, values)
+			assert.Equal(t, expected, p.EvalBits(bits), "EvalBits %v with %v", n, values)
+			assert.Equal(t, expected, mustCompile(Flatten(n)).Eval(values), "Eval flattened %v with %v", n, values)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	cases := []struct {
+		desc    string
+		fixture Node
+		err     *CompileError
+	}{
+		{
+			"Should fail on a nil tree",
+			nil,
+			&CompileError{Reason: "nil node"},
+		},
+		{
+			"Should fail on a nil node",
+			&Op{Left: &Leaf{1}, Val: "AND"},
+			&CompileError{Op: "AND", Reason: "nil node at 1"},
+		},
+		{
+			"Should fail on a bad operation",
+			&Op{Left: &Leaf{1}, Val: "FOO", Right: &Leaf{2}},
+			&CompileError{Op: "FOO", Reason: "bad operation"},
+		},
+		{
+			"Should fail on an empty group",
+			&Group{Val: "OR"},
+			&CompileError{Op: "OR", Reason: "empty group"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			_, err := Compile(c.fixture)
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
+
+func TestProgramDoesNotAllocate(t *testing.T) {
+	p := mustCompile(benchTree())
+	values := make([]bool, p.Leaves)
+	bits := make([]uint64, 1)
+
+	assert.Equal(t, 0.0, testing.AllocsPerRun(100, func() { p.Eval(values) }))
+	assert.Equal(t, 0.0, testing.AllocsPerRun(100, func() { p.EvalBits(bits) }))
+}
+
+func BenchmarkMatch(b *testing.B) {
+	n := benchTree()
+	values := benchValues()
+	value := func(v uint) bool { return values[v] }
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Match(n, value)
+	}
+}
+
+func BenchmarkProgramEval(b *testing.B) {
+	p := mustCompile(benchTree())
+	values := benchValues()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.Eval(values)
+	}
+}
+
+func BenchmarkProgramEvalBits(b *testing.B) {
+	p := mustCompile(benchTree())
+	values := benchValues()
+	bits := make([]uint64, 1)
+	for i, v := range values {
+		if v {
+			bits[0] |= 1 << uint(i)
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.EvalBits(bits)
+	}
+}
+
+func benchTree() Node {
+	n, err := Parse("1 OR (5 AND (7 OR 8)) AND (3 AND 2 OR (56 AND 10) OR 4) AND (11 OR 12 OR 13 AND 14)")
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func benchValues() []bool {
+	values := make([]bool, 57)
+	for _, v := range []int{2, 3, 7, 12, 14} {
+		values[v] = true
+	}
+	return values
+}
+
+func mustCompile(n Node) *Program {
+	p, err := Compile(n)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
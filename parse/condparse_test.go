@@ -150,7 +150,20 @@ func deepEql(assert *assert.Assertions, expected Node, actual Node) {
 		} else if e != nil || a != nil {
 			assert.Fail(fmt.Sprintf("One was nil and the other had a value. Expected %v, Actual %v", e, a))
 		}
+	case *Group:
+		a, isGroup := actual.(*Group)
+		assert.True(isGroup, "Expected was a Group, actual was not! expected %v, actual %v", expected, actual)
+		if e != nil && a != nil {
+			assert.Equal(e.Val, a.Val, "Expected operation to be the same")
+			if assert.Len(a.Nodes, len(e.Nodes), "Expected groups to be the same size") {
+				for i := range e.Nodes {
+					deepEql(assert, e.Nodes[i], a.Nodes[i])
+				}
+			}
+		} else if e != nil || a != nil {
+			assert.Fail(fmt.Sprintf("One was nil and the other had a value. Expected %v, Actual %v", e, a))
+		}
 	default:
-		assert.Fail(fmt.Sprintf("Node was neither a leaf or an op but was instead %#v", e))
+		assert.Fail(fmt.Sprintf("Node was neither a leaf, an op nor a group but was instead %#v", e))
 	}
 }
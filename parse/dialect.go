@@ -0,0 +1,250 @@
+package parse
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// Dialect is a flavor of condition logic. Trees are the same no matter which
+// dialect they came from, so parsing with one dialect and serializing with
+// another converts logic between them without changing what it means.
+type Dialect int
+
+// Define the dialects
+const (
+	// LeftToRight is what Parse and Eval use. AND and OR have the same
+	// precedence and are read left to right, so "1 OR 2 AND 3" means
+	// "(1 OR 2) AND 3".
+	LeftToRight Dialect = iota
+	// Salesforce is filter logic the way Salesforce takes it. AND and OR can't
+	// be mixed without parentheses, so there is never any question of
+	// precedence. Operations can be any case, and conditions are numbered
+	// from 1 with every one of them used.
+	Salesforce
+)
+
+// ParseDialect will parse a logic string in the given dialect
+//	ParseDialect("1 AND 2 OR 3", Salesforce)
+// Will fail, since AND and OR are mixed without parentheses
+func ParseDialect(logic string, d Dialect) (Node, error) {
+	if d != Salesforce {
+		return Parse(logic)
+	}
+
+	// Operations are ASCII, so upper casing them leaves every position as is
+	upper := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII {
+			return unicode.ToUpper(r)
+		}
+		return r
+	}, logic)
+
+	// The lexer fails the same way Parse does, so its errors point at the
+	// caller's logic too
+	n, err := Parse(upper)
+	var tokens []word
+	if err == nil {
+		tokens, err = lex(upper)
+	}
+	if err != nil {
+		if perr, ok := err.(*ParseError); ok {
+			perr.Logic = logic
+		}
+		return nil, err
+	}
+
+	// Parse drops parentheses that don't change the tree, so look for mixed
+	// operations in the logic itself
+	levels := []string{""}
+	first := map[uint]int{}
+	for _, t := range tokens {
+		switch {
+		case t.text == "(":
+			levels = append(levels, "")
+		case t.text == ")":
+			levels = levels[:len(levels)-1]
+		case t.number:
+			var v uint
+			fmt.Sscan(t.text, &v)
+			if _, ok := first[v]; !ok {
+				first[v] = t.pos
+			}
+		default:
+			op := &levels[len(levels)-1]
+			if *op != "" && *op != t.text {
+				return nil, &ParseError{
+					Position: t.pos,
+					Logic:    logic,
+					Reason:   fmt.Sprintf("%s can't be mixed with %s without parentheses", t.text, *op),
+				}
+			}
+			*op = t.text
+		}
+	}
+
+	if pos, ok := first[0]; ok {
+		return nil, &ParseError{
+			Position: pos,
+			Logic:    logic,
+			Reason:   "condition 0 does not exist, conditions start at 1",
+		}
+	}
+
+	if v, ok := unused(n); ok {
+		return nil, &ParseError{
+			Position: len(logic),
+			Logic:    logic,
+			Reason:   fmt.Sprintf("condition %d is not used", v),
+		}
+	}
+
+	return n, nil
+}
+
+// unused finds the first condition from 1 up to the highest leaf that no leaf
+// uses
+func unused(n Node) (uint, bool) {
+	s := Stats(n)
+	for v := uint(1); v < s.Max; v++ {
+		if !s.Leaves.Has(int(v)) {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// EvalDialect will write the tree as logic in the given dialect.
+//	n, _ := Parse("1 OR 2 AND 3")
+//	EvalDialect(n, w, Salesforce)
+// Will write "(1 OR 2) AND 3". A nil tree, which is what empty logic parses to,
+// writes nothing.
+func EvalDialect(n Node, w io.Writer, d Dialect) error {
+	if n == nil {
+		return nil
+	}
+
+	if d != Salesforce {
+		return n.Eval(w)
+	}
+
+	if Stats(n).Leaves.Has(0) {
+		return &SerializeError{
+			Reason: "condition 0 does not exist, conditions start at 1",
+		}
+	}
+
+	if v, ok := unused(n); ok {
+		return &SerializeError{
+			Reason: fmt.Sprintf("condition %d is not used", v),
+		}
+	}
+
+	return evalParens(n, w)
+}
+
+// evalParens writes the tree with parentheses around every operation that
+// sits under a different one
+func evalParens(n Node, w io.Writer) error {
+	var children []Node
+	var op string
+
+	switch node := n.(type) {
+	case nil:
+		return &SerializeError{
+			Reason: "nil node",
+		}
+	case *Op:
+		if node.Left == nil {
+			return &SerializeError{
+				Op:     node.Val,
+				Reason: "nil left node",
+			}
+		}
+		if node.Right == nil {
+			return &SerializeError{
+				Op:     node.Val,
+				Reason: "nil right node",
+			}
+		}
+		op = node.Val
+		children = []Node{node.Left, node.Right}
+	case *Group:
+		if len(node.Nodes) == 1 {
+			return evalParens(node.Nodes[0], w)
+		}
+		if len(node.Nodes) == 0 {
+			return &SerializeError{
+				Op:     node.Val,
+				Reason: "empty group",
+			}
+		}
+		op = node.Val
+		children = node.Nodes
+	default:
+		return n.Eval(w)
+	}
+
+	if !validOp(op) {
+		return &SerializeError{
+			Op:     op,
+			Reason: "bad operation",
+		}
+	}
+
+	for i, c := range children {
+		if c == nil {
+			return &SerializeError{
+				Op:     op,
+				Reason: fmt.Sprintf("nil node at %d", i),
+			}
+		}
+
+		if i > 0 {
+			if _, err := fmt.Fprintf(w, " %s ", op); err != nil {
+				return err
+			}
+		}
+
+		inner := ""
+		switch cn := c.(type) {
+		case *Op:
+			inner = cn.Val
+		case *Group:
+			if len(cn.Nodes) > 1 {
+				inner = cn.Val
+			}
+		}
+		parens := inner != "" && inner != op
+
+		if parens {
+			fmt.Fprint(w, "(")
+		}
+		if err := evalParens(c, w); err != nil {
+			return err
+		}
+		if parens {
+			fmt.Fprint(w, ")")
+		}
+	}
+
+	return nil
+}
+
+// Convert will parse logic in one dialect and write it in another, keeping
+// what it means.
+//	Convert("1 OR 2 AND 3", LeftToRight, Salesforce)
+// Will return "(1 OR 2) AND 3"
+func Convert(logic string, from Dialect, to Dialect) (string, error) {
+	n, err := ParseDialect(logic, from)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := EvalDialect(n, &b, to); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,281 @@
+package parse
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDialect(t *testing.T) {
+	cases := []struct {
+		desc     string
+		logic    string
+		dialect  Dialect
+		expected string
+		err      *ParseError
+	}{
+		{
+			"Should parse left to right like Parse",
+			"1 OR 2 AND 3",
+			LeftToRight,
+			"1 OR 2 AND 3",
+			nil,
+		},
+		{
+			"Should parse parenthesized logic",
+			"(1 OR 2) AND 3",
+			Salesforce,
+			"1 OR 2 AND 3",
+			nil,
+		},
+		{
+			"Should parse operations in any case",
+			"1 and (2 Or 3)",
+			Salesforce,
+			"1 AND (2 OR 3)",
+			nil,
+		},
+		{
+			"Should fail with mixed operations",
+			"1 AND 2 OR 3",
+			Salesforce,
+			"",
+			&ParseError{
+				Position: 8,
+				Logic:    "1 AND 2 OR 3",
+				Reason:   "OR can't be mixed with AND without parentheses",
+			},
+		},
+		{
+			"Should fail with mixed operations inside parentheses",
+			"1 AND (2 OR 3 and 4)",
+			Salesforce,
+			"",
+			&ParseError{
+				Position: 14,
+				Logic:    "1 AND (2 OR 3 and 4)",
+				Reason:   "AND can't be mixed with OR without parentheses",
+			},
+		},
+		{
+			"Should fail with mixed operations around parentheses",
+			"1 OR (2 AND 3) AND 4",
+			Salesforce,
+			"",
+			&ParseError{
+				Position: 15,
+				Logic:    "1 OR (2 AND 3) AND 4",
+				Reason:   "AND can't be mixed with OR without parentheses",
+			},
+		},
+		{
+			"Should fail with condition 0",
+			"0 OR 1",
+			Salesforce,
+			"",
+			&ParseError{
+				Position: 0,
+				Logic:    "0 OR 1",
+				Reason:   "condition 0 does not exist, conditions start at 1",
+			},
+		},
+		{
+			"Should fail with an unused condition",
+			"1 AND 3",
+			Salesforce,
+			"",
+			&ParseError{
+				Position: 7,
+				Logic:    "1 AND 3",
+				Reason:   "condition 2 is not used",
+			},
+		},
+		{
+			"Should keep the original logic in errors from Parse",
+			"1 and",
+			Salesforce,
+			"",
+			&ParseError{
+				Position: 2,
+				Logic:    "1 and",
+				Reason:   "unexpected operation",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			n, err := ParseDialect(c.logic, c.dialect)
+			if c.err != nil {
+				assert.Equal(t, c.err, err)
+				assert.Nil(t, n)
+				return
+			}
+
+			assert.NoError(t, err)
+			var b strings.Builder
+			assert.NoError(t, n.Eval(&b))
+			assert.Equal(t, c.expected, b.String())
+		})
+	}
+}
+
+func TestEvalDialect(t *testing.T) {
+	cases := []struct {
+		desc     string
+		tree     Node
+		expected string
+		err      error
+	}{
+		{
+			"Should write a leaf",
+			&Leaf{1},
+			"1",
+			nil,
+		},
+		{
+			"Should parenthesize a left operation",
+			&Op{
+				Left:  &Op{&Leaf{1}, "OR", &Leaf{2}},
+				Val:   "AND",
+				Right: &Leaf{3},
+			},
+			"(1 OR 2) AND 3",
+			nil,
+		},
+		{
+			"Should not parenthesize the same operation",
+			&Op{
+				Left:  &Leaf{1},
+				Val:   "AND",
+				Right: &Op{&Leaf{2}, "AND", &Leaf{3}},
+			},
+			"1 AND 2 AND 3",
+			nil,
+		},
+		{
+			"Should parenthesize groups",
+			&Group{
+				Val: "OR",
+				Nodes: []Node{
+					&Group{Val: "AND", Nodes: []Node{&Leaf{1}, &Leaf{2}}},
+					&Leaf{3},
+					&Group{Val: "AND", Nodes: []Node{&Leaf{4}}},
+				},
+			},
+			"(1 AND 2) OR 3 OR 4",
+			nil,
+		},
+		{
+			"Should fail with an unused condition",
+			&Op{&Leaf{1}, "OR", &Leaf{3}},
+			"",
+			&SerializeError{
+				Reason: "condition 2 is not used",
+			},
+		},
+		{
+			"Should fail with a bad operation",
+			&Op{&Leaf{1}, "XOR", &Leaf{2}},
+			"",
+			&SerializeError{
+				Op:     "XOR",
+				Reason: "bad operation",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			var b strings.Builder
+			err := EvalDialect(c.tree, &b, Salesforce)
+			if c.err != nil {
+				assert.Equal(t, c.err, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, b.String())
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	cases := []struct {
+		desc     string
+		logic    string
+		from     Dialect
+		to       Dialect
+		expected string
+	}{
+		{
+			"Should parenthesize left to right logic",
+			"1 OR 2 AND 3 OR 4",
+			LeftToRight,
+			Salesforce,
+			"((1 OR 2) AND 3) OR 4",
+		},
+		{
+			"Should keep right hand groups",
+			"1 AND (2 OR 3 OR 4)",
+			LeftToRight,
+			Salesforce,
+			"1 AND (2 OR 3 OR 4)",
+		},
+		{
+			"Should convert back to left to right",
+			"((1 OR 2) AND 3) OR 4",
+			Salesforce,
+			LeftToRight,
+			"1 OR 2 AND 3 OR 4",
+		},
+		{
+			"Should keep the meaning of right hand groups",
+			"1 or (2 and 3)",
+			Salesforce,
+			LeftToRight,
+			"1 OR (2 AND 3)",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			actual, err := Convert(c.logic, c.from, c.to)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, actual)
+
+			back, err := Convert(actual, c.to, c.from)
+			assert.NoError(t, err)
+			a, _ := ParseDialect(c.logic, c.from)
+			b, _ := ParseDialect(back, c.from)
+			assert.True(t, Equal(a, b))
+		})
+	}
+}
+
+func TestDialectEmpty(t *testing.T) {
+	for _, d := range []Dialect{LeftToRight, Salesforce} {
+		n, err := ParseDialect("", d)
+		assert.NoError(t, err)
+		assert.Nil(t, n)
+
+		var b strings.Builder
+		assert.NoError(t, EvalDialect(nil, &b, d))
+		assert.Equal(t, "", b.String())
+	}
+
+	for _, logic := range []string{"", "  "} {
+		actual, err := Convert(logic, LeftToRight, Salesforce)
+		assert.NoError(t, err)
+		assert.Equal(t, "", actual)
+
+		actual, err = Convert(logic, Salesforce, LeftToRight)
+		assert.NoError(t, err)
+		assert.Equal(t, "", actual)
+	}
+
+	var b strings.Builder
+	err := EvalDialect(&Group{Val: "AND", Nodes: []Node{nil}}, &b, Salesforce)
+	assert.Equal(t, &SerializeError{Reason: "nil node"}, err)
+}
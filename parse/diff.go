@@ -0,0 +1,350 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Equal will check if two trees are exactly the same, node for node. It does
+// not treat "1 AND 2" and "2 AND 1" as equal, use Hash or Canonicalize for that.
+func Equal(a Node, b Node) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	switch x := a.(type) {
+	case *Leaf:
+		y, ok := b.(*Leaf)
+		return ok && x.Val == y.Val
+	case *Op:
+		y, ok := b.(*Op)
+		return ok && x.Val == y.Val && Equal(x.Left, y.Left) && Equal(x.Right, y.Right)
+	case *Group:
+		y, ok := b.(*Group)
+		if !ok || x.Val != y.Val || len(x.Nodes) != len(y.Nodes) {
+			return false
+		}
+		for i := range x.Nodes {
+			if !Equal(x.Nodes[i], y.Nodes[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+// ChangeKind is the kind of change found by Diff
+type ChangeKind int
+
+// Define the kinds of changes
+const (
+	Added ChangeKind = iota
+	Removed
+	Moved
+	OpChanged
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Moved:
+		return "moved"
+	case OpChanged:
+		return "operation changed"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// Change is a single difference between two trees. From is the path in the
+// old tree and To is the path in the new tree, both in their flattened form.
+// For Added, Removed and Moved, FromOp and ToOp are the operations of the
+// groups holding the node. For OpChanged they are the operations of the group
+// itself.
+type Change struct {
+	Kind   ChangeKind
+	Node   Node
+	From   Path
+	To     Path
+	FromOp string
+	ToOp   string
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("%s was added to %s at %v", describe(c.Node), describeOp(c.ToOp), c.To)
+	case Removed:
+		return fmt.Sprintf("%s was removed from %s at %v", describe(c.Node), describeOp(c.FromOp), c.From)
+	case Moved:
+		return fmt.Sprintf("%s was moved from %s at %v to %s at %v", describe(c.Node), describeOp(c.FromOp), c.From, describeOp(c.ToOp), c.To)
+	case OpChanged:
+		return fmt.Sprintf("operation at %v was changed from %s to %s", c.To, c.FromOp, c.ToOp)
+	}
+	return c.Kind.String()
+}
+
+func describe(n Node) string {
+	if l, ok := n.(*Leaf); ok {
+		return fmt.Sprintf("condition %d", l.Val)
+	}
+
+	var b strings.Builder
+	if err := n.Eval(&b); err != nil {
+		return fmt.Sprintf("group %v", n)
+	}
+	return fmt.Sprintf("group (%s)", b.String())
+}
+
+func describeOp(op string) string {
+	if op == "" {
+		return "the top level"
+	}
+	return fmt.Sprintf("an %s group", op)
+}
+
+type entry struct {
+	node     Node
+	path     Path
+	parent   string
+	parentOp string
+}
+
+// Diff will compare two trees and list what it would take to get from a to
+// b. Both trees are flattened first, so only real changes in grouping are
+// reported, and the order of the nodes within a group is ignored. Groups are
+// matched up with the group in the other tree that shares the most leaves, and
+// whole groups that show up unchanged somewhere else are reported as moved once
+// rather than leaf by leaf.
+//	a, _ := Parse("1 AND 4 AND (2 OR 3)")
+//	b, _ := Parse("1 AND (2 OR 3 OR 4)")
+//	Diff(a, b)
+// Will report that condition 4 was moved from an AND group to an OR group
+func Diff(a Node, b Node) []Change {
+	fa := Flatten(a)
+	fb := Flatten(b)
+
+	if Equal(fa, fb) {
+		return nil
+	}
+
+	ea := entries(fa)
+	eb := entries(fb)
+
+	// pairs maps the path of a group in a to the path of its group in b, and
+	// paired is the other way around. Nodes at the top have no parent, which
+	// is always paired up.
+	pairs := map[string]string{"": ""}
+	paired := map[string]string{"": ""}
+
+	var changes []Change
+
+	if ga, ok := fa.(*Group); ok {
+		if gb, ok := fb.(*Group); ok {
+			pairs["/"] = "/"
+			paired["/"] = "/"
+			if ga.Val != gb.Val {
+				changes = append(changes, Change{
+					Kind:   OpChanged,
+					Node:   gb,
+					From:   Path{},
+					To:     Path{},
+					FromOp: ga.Val,
+					ToOp:   gb.Val,
+				})
+			}
+		}
+	}
+
+	// Pair up each group with the group under its paired parent that shares
+	// the most leaves with it
+	for _, x := range ea {
+		g, ok := x.node.(*Group)
+		if !ok {
+			continue
+		}
+		key := x.path.String()
+		if _, ok := pairs[key]; ok {
+			continue
+		}
+		parent, ok := pairs[x.parent]
+		if !ok {
+			continue
+		}
+
+		var best *entry
+		bestScore := 0
+		for i, y := range eb {
+			if _, ok := y.node.(*Group); !ok || y.parent != parent {
+				continue
+			}
+			if _, ok := paired[y.path.String()]; ok {
+				continue
+			}
+			if score := overlap(x.node, y.node); score > bestScore {
+				best = &eb[i]
+				bestScore = score
+			}
+		}
+		if best == nil {
+			continue
+		}
+
+		pairs[key] = best.path.String()
+		paired[best.path.String()] = key
+
+		if h := best.node.(*Group); h.Val != g.Val {
+			changes = append(changes, Change{
+				Kind:   OpChanged,
+				Node:   h,
+				From:   x.path,
+				To:     best.path,
+				FromOp: g.Val,
+				ToOp:   h.Val,
+			})
+		}
+	}
+
+	// Any group that is left over but shows up unchanged somewhere else was
+	// moved as a whole
+	for _, x := range ea {
+		if _, ok := x.node.(*Group); !ok {
+			continue
+		}
+		if _, ok := pairs[x.path.String()]; ok {
+			continue
+		}
+		for _, y := range eb {
+			if _, ok := paired[y.path.String()]; ok || !Equal(x.node, y.node) {
+				continue
+			}
+			pairAll(pairs, paired, x.node, x.path, y.path)
+			changes = append(changes, moved(x, y))
+			break
+		}
+	}
+
+	// Match up leaves that are still under the same group, and then leaves
+	// that ended up under another group
+	seenA := map[string]bool{}
+	seenB := map[string]bool{}
+	for _, x := range ea {
+		if _, ok := x.node.(*Leaf); !ok {
+			continue
+		}
+		parent, ok := pairs[x.parent]
+		if !ok {
+			continue
+		}
+		for _, y := range eb {
+			if y.parent == parent && !seenB[y.path.String()] && Equal(x.node, y.node) {
+				seenA[x.path.String()] = true
+				seenB[y.path.String()] = true
+				break
+			}
+		}
+	}
+	for _, x := range ea {
+		if _, ok := x.node.(*Leaf); !ok || seenA[x.path.String()] {
+			continue
+		}
+		for _, y := range eb {
+			if _, ok := y.node.(*Leaf); !ok || seenB[y.path.String()] || !Equal(x.node, y.node) {
+				continue
+			}
+			seenA[x.path.String()] = true
+			seenB[y.path.String()] = true
+			changes = append(changes, moved(x, y))
+			break
+		}
+	}
+
+	for _, x := range ea {
+		if _, ok := x.node.(*Leaf); ok && !seenA[x.path.String()] {
+			changes = append(changes, Change{
+				Kind:   Removed,
+				Node:   x.node,
+				From:   x.path,
+				FromOp: x.parentOp,
+			})
+		}
+	}
+	for _, y := range eb {
+		if _, ok := y.node.(*Leaf); ok && !seenB[y.path.String()] {
+			changes = append(changes, Change{
+				Kind: Added,
+				Node: y.node,
+				To:   y.path,
+				ToOp: y.parentOp,
+			})
+		}
+	}
+
+	return changes
+}
+
+func moved(x entry, y entry) Change {
+	return Change{
+		Kind:   Moved,
+		Node:   x.node,
+		From:   x.path,
+		To:     y.path,
+		FromOp: x.parentOp,
+		ToOp:   y.parentOp,
+	}
+}
+
+// entries lists every node in the tree along with its path and the group or
+// Op holding it
+func entries(n Node) []entry {
+	var e []entry
+	WalkPaths(n, func(c Node, p Path) {
+		var parent, op string
+		if len(p) > 0 {
+			parent = p[:len(p)-1].String()
+			switch node := At(n, p[:len(p)-1]).(type) {
+			case *Op:
+				op = node.Val
+			case *Group:
+				op = node.Val
+			}
+		}
+		e = append(e, entry{c, p, parent, op})
+	})
+	return e
+}
+
+// overlap counts how many leaves two trees have in common
+func overlap(a Node, b Node) int {
+	counts := map[uint]int{}
+	WalkLeaves(a, func(n Node) Node {
+		counts[n.(*Leaf).Val]++
+		return n
+	})
+
+	score := 0
+	WalkLeaves(b, func(n Node) Node {
+		if v := n.(*Leaf).Val; counts[v] > 0 {
+			counts[v]--
+			score++
+		}
+		return n
+	})
+	return score
+}
+
+// pairAll will pair up every group under two equal trees
+func pairAll(pairs map[string]string, paired map[string]string, n Node, from Path, to Path) {
+	walkPaths(n, Path{}, func(c Node, p Path) {
+		if _, ok := c.(*Group); ok {
+			f := append(append(Path{}, from...), p...).String()
+			t := append(append(Path{}, to...), p...).String()
+			pairs[f] = t
+			paired[t] = f
+		}
+	})
+}
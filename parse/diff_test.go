@@ -0,0 +1,176 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEqual(t *testing.T) {
+	cases := []struct {
+		desc     string
+		a        Node
+		b        Node
+		expected bool
+	}{
+		{
+			"Should match two nils",
+			nil,
+			nil,
+			true,
+		},
+		{
+			"Should not match nil and a leaf",
+			nil,
+			&Leaf{1},
+			false,
+		},
+		{
+			"Should match leaves",
+			&Leaf{1},
+			&Leaf{1},
+			true,
+		},
+		{
+			"Should not match different leaves",
+			&Leaf{1},
+			&Leaf{2},
+			false,
+		},
+		{
+			"Should match operations",
+			&Op{Left: &Leaf{1}, Val: "AND", Right: &Leaf{2}},
+			&Op{Left: &Leaf{1}, Val: "AND", Right: &Leaf{2}},
+			true,
+		},
+		{
+			"Should not match commuted operations",
+			&Op{Left: &Leaf{1}, Val: "AND", Right: &Leaf{2}},
+			&Op{Left: &Leaf{2}, Val: "AND", Right: &Leaf{1}},
+			false,
+		},
+		{
+			"Should not match an operation and a group",
+			&Op{Left: &Leaf{1}, Val: "AND", Right: &Leaf{2}},
+			&Group{Val: "AND", Nodes: []Node{&Leaf{1}, &Leaf{2}}},
+			false,
+		},
+		{
+			"Should match groups",
+			&Group{Val: "OR", Nodes: []Node{&Leaf{1}, &Leaf{2}, &Leaf{3}}},
+			&Group{Val: "OR", Nodes: []Node{&Leaf{1}, &Leaf{2}, &Leaf{3}}},
+			true,
+		},
+		{
+			"Should not match groups of different sizes",
+			&Group{Val: "OR", Nodes: []Node{&Leaf{1}, &Leaf{2}, &Leaf{3}}},
+			&Group{Val: "OR", Nodes: []Node{&Leaf{1}, &Leaf{2}}},
+			false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			assert.Equal(c.expected, Equal(c.a, c.b))
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	cases := []struct {
+		desc     string
+		a        string
+		b        string
+		expected []string
+	}{
+		{
+			"Should not find changes in the same logic",
+			"1 AND 2 AND 3",
+			"1 AND (2 AND 3)",
+			nil,
+		},
+		{
+			"Should find an added leaf",
+			"1 AND 2",
+			"1 AND 2 AND 3",
+			[]string{
+				"condition 3 was added to an AND group at /2",
+			},
+		},
+		{
+			"Should find a removed leaf",
+			"1 OR 2 OR 3",
+			"1 OR 3",
+			[]string{
+				"condition 2 was removed from an OR group at /1",
+			},
+		},
+		{
+			"Should find a leaf moved into another group",
+			"1 AND 4 AND (2 OR 3)",
+			"1 AND (2 OR 3 OR 4)",
+			[]string{
+				"condition 4 was moved from an AND group at /1 to an OR group at /1/2",
+			},
+		},
+		{
+			"Should find a changed operation",
+			"1 AND 2",
+			"1 OR 2",
+			[]string{
+				"operation at / was changed from AND to OR",
+			},
+		},
+		{
+			"Should find changed operations in nested groups",
+			"1 AND (2 OR 3)",
+			"1 OR (2 AND 3)",
+			[]string{
+				"operation at / was changed from AND to OR",
+				"operation at /1 was changed from OR to AND",
+			},
+		},
+		{
+			"Should ignore the order of nodes in a group",
+			"(1 OR 2) AND 3 AND (4 OR 5)",
+			"3 AND (4 OR 5) AND (2 OR 1)",
+			nil,
+		},
+		{
+			"Should find a moved group",
+			"(1 AND 2) OR (3 AND (4 OR 5))",
+			"(1 AND 2 AND (4 OR 5)) OR 3",
+			[]string{
+				"group (4 OR 5) was moved from an AND group at /1/1 to an AND group at /0/2",
+				"condition 3 was moved from an AND group at /1/0 to an OR group at /1",
+			},
+		},
+		{
+			"Should find added and removed leaves in the same group",
+			"1 AND 2 AND 3",
+			"1 AND 4 AND 3 AND 5",
+			[]string{
+				"condition 2 was removed from an AND group at /1",
+				"condition 4 was added to an AND group at /1",
+				"condition 5 was added to an AND group at /3",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			a, err := Parse(c.a)
+			assert.NoError(err)
+			b, err := Parse(c.b)
+			assert.NoError(err)
+
+			var actual []string
+			for _, change := range Diff(a, b) {
+				actual = append(actual, change.String())
+			}
+			assert.Equal(c.expected, actual)
+		})
+	}
+}
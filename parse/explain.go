@@ -0,0 +1,223 @@
+package parse
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/tools/container/intsets"
+)
+
+// Explanation is the result of evaluating a tree along with why it came out
+// that way
+type Explanation struct {
+	Result bool `json:"result"`
+	// Reasons are the leaves that decided the result. They all have the same
+	// value as the result, and no other leaves are needed to get it.
+	Reasons []uint `json:"reasons"`
+	Trace   *Trace `json:"trace"`
+}
+
+// Trace is what happened to a single node while evaluating a tree. A node
+// that was never evaluated, because the result was already known, is skipped.
+type Trace struct {
+	Logic   string   `json:"logic"`
+	Leaf    *uint    `json:"leaf,omitempty"`
+	Op      string   `json:"op,omitempty"`
+	Value   bool     `json:"value"`
+	Skipped bool     `json:"skipped,omitempty"`
+	Nodes   []*Trace `json:"nodes,omitempty"`
+}
+
+// Explain will evaluate the tree the same way Match does and keep track of
+// every node it evaluated, and every node it skipped. It also works out the
+// smallest set of leaves that decide the result by picking the smallest one
+// wherever more than one would do, so value may be asked about every leaf.
+//	n, _ := Parse("1 AND (2 OR 3) AND 4")
+//	e := Explain(n, func(v uint) bool { return v != 2 })
+//	e.String()
+// Will return "matched because 1, 3 and 4 were true"
+func Explain(n Node, value func(uint) bool) *Explanation {
+	t := trace(n, value)
+	_, reasons := justify(n, value)
+
+	e := &Explanation{
+		Result:  t.Value,
+		Reasons: make([]uint, 0, reasons.Len()),
+		Trace:   t,
+	}
+	for _, v := range reasons.AppendTo(nil) {
+		e.Reasons = append(e.Reasons, uint(v))
+	}
+	return e
+}
+
+func trace(n Node, value func(uint) bool) *Trace {
+	t := &Trace{
+		Logic: logic(n),
+	}
+
+	var children []Node
+	switch node := n.(type) {
+	case *Leaf:
+		v := node.Val
+		t.Leaf = &v
+		t.Value = value(v)
+		return t
+	case *Op:
+		t.Op = node.Val
+		children = []Node{node.Left, node.Right}
+	case *Group:
+		t.Op = node.Val
+		children = node.Nodes
+	default:
+		return t
+	}
+
+	and := t.Op == "AND"
+	t.Value = and
+	decided := false
+	for _, c := range children {
+		if decided {
+			t.Nodes = append(t.Nodes, skipped(c))
+			continue
+		}
+		ct := trace(c, value)
+		t.Nodes = append(t.Nodes, ct)
+		if ct.Value != and {
+			t.Value = !and
+			decided = true
+		}
+	}
+	return t
+}
+
+func skipped(n Node) *Trace {
+	t := &Trace{
+		Logic:   logic(n),
+		Skipped: true,
+	}
+	switch node := n.(type) {
+	case *Leaf:
+		v := node.Val
+		t.Leaf = &v
+	case *Op:
+		t.Op = node.Val
+	case *Group:
+		t.Op = node.Val
+	}
+	return t
+}
+
+// justify evaluates every node and returns its value along with the leaves
+// that decide it
+func justify(n Node, value func(uint) bool) (bool, *intsets.Sparse) {
+	var op string
+	var children []Node
+
+	switch node := n.(type) {
+	case *Leaf:
+		s := &intsets.Sparse{}
+		s.Insert(int(node.Val))
+		return value(node.Val), s
+	case *Op:
+		op = node.Val
+		children = []Node{node.Left, node.Right}
+	case *Group:
+		op = node.Val
+		children = node.Nodes
+	default:
+		return false, &intsets.Sparse{}
+	}
+
+	// With AND, every child has to be true, but any one false child will do.
+	// OR is the other way around.
+	and := op == "AND"
+	all := &intsets.Sparse{}
+	var best *intsets.Sparse
+	for _, c := range children {
+		v, s := justify(c, value)
+		if v == and {
+			all.UnionWith(s)
+		} else if best == nil || s.Len() < best.Len() {
+			best = s
+		}
+	}
+
+	if best != nil {
+		return !and, best
+	}
+	return and, all
+}
+
+func logic(n Node) string {
+	if n == nil {
+		return ""
+	}
+	var b strings.Builder
+	if err := n.Eval(&b); err != nil {
+		return fmt.Sprintf("%v", n)
+	}
+	return b.String()
+}
+
+// String will sum up the explanation in a sentence like "matched because 1
+// and 4 were true"
+func (e *Explanation) String() string {
+	vals := make([]string, len(e.Reasons))
+	for i, v := range e.Reasons {
+		vals[i] = fmt.Sprintf("%d", v)
+	}
+
+	var leaves string
+	switch len(vals) {
+	case 0:
+		return "did not match because there was nothing to match"
+	case 1:
+		leaves = vals[0] + " was"
+	default:
+		leaves = strings.Join(vals[:len(vals)-1], ", ") + " and " + vals[len(vals)-1] + " were"
+	}
+
+	if e.Result {
+		return fmt.Sprintf("matched because %s true", leaves)
+	}
+	return fmt.Sprintf("did not match because %s false", leaves)
+}
+
+// WriteText will write the summary from String, and then the trace as an
+// indented outline with each node on a line along with its value, or skipped
+// if it was never evaluated. For 1 AND (2 OR 3) AND 4 with only 1 and 4
+// true, it writes
+//	did not match because 2 and 3 were false
+//	false: 1 AND (2 OR 3) AND 4
+//	  false: 1 AND (2 OR 3)
+//	    true: 1
+//	    false: 2 OR 3
+//	      false: 2
+//	      false: 3
+//	  skipped: 4
+func (e *Explanation) WriteText(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, e); err != nil {
+		return err
+	}
+	return e.Trace.writeText(w, 0)
+}
+
+func (t *Trace) writeText(w io.Writer, depth int) error {
+	status := fmt.Sprintf("%t", t.Value)
+	if t.Skipped {
+		status = "skipped"
+	}
+
+	if _, err := fmt.Fprintf(w, "%s%s: %s\n", strings.Repeat("  ", depth), status, t.Logic); err != nil {
+		return err
+	}
+
+	for _, c := range t.Nodes {
+		if err := c.writeText(w, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package parse
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	cases := []struct {
+		desc     string
+		logic    string
+		values   map[uint]bool
+		result   bool
+		reasons  []uint
+		expected string
+	}{
+		{
+			"Should explain a single leaf",
+			"1",
+			map[uint]bool{1: true},
+			true,
+			[]uint{1},
+			"matched because 1 was true",
+		},
+		{
+			"Should explain a match",
+			"1 AND (2 OR 3) AND 4",
+			map[uint]bool{1: true, 2: false, 3: true, 4: true},
+			true,
+			[]uint{1, 3, 4},
+			"matched because 1, 3 and 4 were true",
+		},
+		{
+			"Should explain a miss",
+			"1 AND (2 OR 3) AND 4",
+			map[uint]bool{1: true, 2: false, 3: false, 4: true},
+			false,
+			[]uint{2, 3},
+			"did not match because 2 and 3 were false",
+		},
+		{
+			"Should pick the smallest reason",
+			"(1 AND 2) OR 3",
+			map[uint]bool{1: true, 2: true, 3: true},
+			true,
+			[]uint{3},
+			"matched because 3 was true",
+		},
+		{
+			"Should pick the smallest reason in a group",
+			"(1 OR 2) AND 3 AND (4 OR 5)",
+			map[uint]bool{1: false, 2: false, 3: true, 4: false, 5: false},
+			false,
+			[]uint{1, 2},
+			"did not match because 1 and 2 were false",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			n, err := Parse(c.logic)
+			assert.NoError(err)
+
+			for _, tree := range []Node{n, Flatten(n)} {
+				e := Explain(tree, func(v uint) bool { return c.values[v] })
+				assert.Equal(c.result, e.Result)
+				assert.Equal(c.reasons, e.Reasons)
+				assert.Equal(c.expected, e.String())
+			}
+		})
+	}
+}
+
+func TestExplainText(t *testing.T) {
+	assert := assert.New(t)
+	n, err := Parse("1 AND (2 OR 3) AND 4")
+	assert.NoError(err)
+
+	e := Explain(n, func(v uint) bool { return v == 1 || v == 4 })
+
+	var b strings.Builder
+	assert.NoError(e.WriteText(&b))
+	assert.Equal(strings.Join([]string{
+		"did not match because 2 and 3 were false",
+		"false: 1 AND (2 OR 3) AND 4",
+		"  false: 1 AND (2 OR 3)",
+		"    true: 1",
+		"    false: 2 OR 3",
+		"      false: 2",
+		"      false: 3",
+		"  skipped: 4",
+		"",
+	}, "\n"), b.String())
+
+	e = Explain(Flatten(n), func(v uint) bool { return v != 3 })
+
+	b.Reset()
+	assert.NoError(e.WriteText(&b))
+	assert.Equal(strings.Join([]string{
+		"matched because 1, 2 and 4 were true",
+		"true: 1 AND (2 OR 3) AND 4",
+		"  true: 1",
+		"  true: 2 OR 3",
+		"    true: 2",
+		"    skipped: 3",
+		"  true: 4",
+		"",
+	}, "\n"), b.String())
+}
+
+func TestExplainJSON(t *testing.T) {
+	assert := assert.New(t)
+	n, err := Parse("1 OR 2")
+	assert.NoError(err)
+
+	e := Explain(n, func(v uint) bool { return v == 1 })
+
+	b, err := json.Marshal(e)
+	assert.NoError(err)
+	assert.JSONEq(`{
+		"result": true,
+		"reasons": [1],
+		"trace": {
+			"logic": "1 OR 2",
+			"op": "OR",
+			"value": true,
+			"nodes": [
+				{"logic": "1", "leaf": 1, "value": true},
+				{"logic": "2", "leaf": 2, "value": false, "skipped": true}
+			]
+		}
+	}`, string(b))
+}
@@ -0,0 +1,86 @@
+package parse
+
+// Flatten will turn every chain of operations that share the same operation
+// into a single Group. Since AND and OR are associative, the flattened tree
+// means the same thing as the original.
+//	n, _ := Parse("1 AND 2 AND (3 AND 4) OR 5")
+//	n = Flatten(n)
+// Will yield OR[AND[1 2 3 4] 5]
+func Flatten(n Node) Node {
+	switch node := n.(type) {
+	case *Op:
+		return flatGroup(node.Val, Flatten(node.Left), Flatten(node.Right))
+	case *Group:
+		nodes := make([]Node, len(node.Nodes))
+		for i, c := range node.Nodes {
+			nodes[i] = Flatten(c)
+		}
+		return flatGroup(node.Val, nodes...)
+	}
+	return n
+}
+
+// flatGroup builds a group out of nodes, pulling up the nodes of any child
+// group with the same operation. Nil nodes are dropped and a group that ends
+// up with a single node is replaced by that node.
+func flatGroup(op string, nodes ...Node) Node {
+	g := &Group{
+		Val:   op,
+		Nodes: make([]Node, 0, len(nodes)),
+	}
+
+	for _, n := range nodes {
+		if n == nil {
+			continue
+		}
+		if c, ok := n.(*Group); ok && c.Val == op {
+			g.Nodes = append(g.Nodes, c.Nodes...)
+		} else {
+			g.Nodes = append(g.Nodes, n)
+		}
+	}
+
+	if len(g.Nodes) == 0 {
+		return nil
+	}
+
+	if len(g.Nodes) == 1 {
+		return g.Nodes[0]
+	}
+
+	return g
+}
+
+// Binarize will turn every Group back into a chain of Ops, reading the nodes
+// left to right the same way Parse does.
+//	Binarize(&Group{Val: "AND", Nodes: []Node{&Leaf{1}, &Leaf{2}, &Leaf{3}}})
+// Will yield 1 <- AND -> 2 <- AND -> 3
+func Binarize(n Node) Node {
+	switch node := n.(type) {
+	case *Op:
+		return &Op{
+			Left:  Binarize(node.Left),
+			Val:   node.Val,
+			Right: Binarize(node.Right),
+		}
+	case *Group:
+		var b Node
+		for _, c := range node.Nodes {
+			c = Binarize(c)
+			if c == nil {
+				continue
+			}
+			if b == nil {
+				b = c
+				continue
+			}
+			b = &Op{
+				Left:  b,
+				Val:   node.Val,
+				Right: c,
+			}
+		}
+		return b
+	}
+	return n
+}
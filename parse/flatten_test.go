@@ -0,0 +1,125 @@
+package parse
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlatten(t *testing.T) {
+	cases := []struct {
+		desc     string
+		logic    string
+		expected Node
+	}{
+		{
+			"Should leave a single leaf alone",
+			"1",
+			&Leaf{1},
+		},
+		{
+			"Should turn a single operation into a group",
+			"1 AND 2",
+			&Group{
+				Val:   "AND",
+				Nodes: []Node{&Leaf{1}, &Leaf{2}},
+			},
+		},
+		{
+			"Should flatten a left chain of the same operation",
+			"1 AND 2 AND 3 AND 4",
+			&Group{
+				Val:   "AND",
+				Nodes: []Node{&Leaf{1}, &Leaf{2}, &Leaf{3}, &Leaf{4}},
+			},
+		},
+		{
+			"Should flatten parens with the same operation",
+			"1 OR (2 OR 3) OR 4",
+			&Group{
+				Val:   "OR",
+				Nodes: []Node{&Leaf{1}, &Leaf{2}, &Leaf{3}, &Leaf{4}},
+			},
+		},
+		{
+			"Should keep mixed operations in nested groups",
+			"1 OR 2 AND 3 AND (4 OR 5 OR 6)",
+			&Group{
+				Val: "AND",
+				Nodes: []Node{
+					&Group{
+						Val:   "OR",
+						Nodes: []Node{&Leaf{1}, &Leaf{2}},
+					},
+					&Leaf{3},
+					&Group{
+						Val:   "OR",
+						Nodes: []Node{&Leaf{4}, &Leaf{5}, &Leaf{6}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			n, err := Parse(c.logic)
+			assert.NoError(err)
+
+			deepEql(assert, c.expected, Flatten(n))
+		})
+	}
+}
+
+func TestFlattenRoundTrip(t *testing.T) {
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var b strings.Builder
+			assert.NoError(Flatten(c.exprTree).Eval(&b))
+			assert.Equal(c.logic, b.String(), "Flattened tree should serialize the same")
+
+			n := Binarize(Flatten(c.exprTree))
+			b.Reset()
+			assert.NoError(n.Eval(&b))
+			assert.Equal(c.logic, b.String(), "Binarized tree should serialize the same")
+		})
+	}
+}
+
+func TestBinarize(t *testing.T) {
+	assert := assert.New(t)
+
+	n := Binarize(&Group{
+		Val: "OR",
+		Nodes: []Node{
+			&Leaf{1},
+			&Group{
+				Val:   "AND",
+				Nodes: []Node{&Leaf{2}, &Leaf{3}, &Leaf{4}},
+			},
+			&Leaf{5},
+		},
+	})
+
+	deepEql(assert, &Op{
+		Left: &Op{
+			Left: &Leaf{1},
+			Val:  "OR",
+			Right: &Op{
+				Left: &Op{
+					Left:  &Leaf{2},
+					Val:   "AND",
+					Right: &Leaf{3},
+				},
+				Val:   "AND",
+				Right: &Leaf{4},
+			},
+		},
+		Val:   "OR",
+		Right: &Leaf{5},
+	}, n)
+}
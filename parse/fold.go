@@ -0,0 +1,56 @@
+package parse
+
+import (
+	"fmt"
+	"io/ioutil"
+)
+
+// LeafFunc builds the value for a leaf
+type LeafFunc func(l *Leaf) (interface{}, error)
+
+// GroupFunc builds the value for a group out of the values already built for
+// each of its nodes, which line up with g.Nodes
+type GroupFunc func(g *Group, values []interface{}) (interface{}, error)
+
+// Fold will build a value for the tree from the bottom up, which is how trees
+// are turned into other query languages. The tree has to be one Eval can
+// serialize, and it is flattened first, so group only ever sees groups with
+// at least two nodes and never a group with the same operation right under
+// it. A nil tree is a SerializeError, and so is anything Eval fails on.
+//	n, _ := Parse("1 AND (2 OR 3)")
+//	Fold(n, leaf, group)
+// Will call group for OR[2 3] and then for AND[1 OR[2 3]]
+func Fold(n Node, leaf LeafFunc, group GroupFunc) (interface{}, error) {
+	if n == nil {
+		return nil, &SerializeError{
+			Reason: "nil tree",
+		}
+	}
+
+	if err := n.Eval(ioutil.Discard); err != nil {
+		return nil, err
+	}
+
+	return fold(Flatten(n), leaf, group)
+}
+
+func fold(n Node, leaf LeafFunc, group GroupFunc) (interface{}, error) {
+	switch node := n.(type) {
+	case *Leaf:
+		return leaf(node)
+	case *Group:
+		values := make([]interface{}, len(node.Nodes))
+		for i, c := range node.Nodes {
+			v, err := fold(c, leaf, group)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+		return group(node, values)
+	}
+
+	return nil, &SerializeError{
+		Reason: fmt.Sprintf("unknown node %T", n),
+	}
+}
@@ -0,0 +1,85 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFold(t *testing.T) {
+	leaf := func(l *Leaf) (interface{}, error) {
+		return fmt.Sprint(l.Val), nil
+	}
+	group := func(g *Group, values []interface{}) (interface{}, error) {
+		parts := make([]string, len(values))
+		for i, v := range values {
+			parts[i] = v.(string)
+		}
+		return fmt.Sprintf("%s(%s)", strings.ToLower(g.Val), strings.Join(parts, ", ")), nil
+	}
+
+	cases := []struct {
+		desc     string
+		tree     Node
+		expected interface{}
+		err      error
+	}{
+		{
+			"Should fold a single leaf",
+			&Leaf{1},
+			"1",
+			nil,
+		},
+		{
+			"Should fold a flattened tree",
+			mustSexp("(AND (AND 1 2) (OR 3 (OR 4 5)))"),
+			"and(1, 2, or(3, 4, 5))",
+			nil,
+		},
+		{
+			"Should collapse a group with one node",
+			mustSexp("(OR [AND 1] 2)"),
+			"or(1, 2)",
+			nil,
+		},
+		{
+			"Should fail with a nil tree",
+			nil,
+			nil,
+			&SerializeError{Reason: "nil tree"},
+		},
+		{
+			"Should fail with a tree Eval fails on",
+			mustSexp("(XOR 1 2)"),
+			nil,
+			&SerializeError{Op: "XOR", Reason: "bad operation"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			actual, err := Fold(c.tree, leaf, group)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestFoldErrors(t *testing.T) {
+	failed := errors.New("failed")
+
+	_, err := Fold(mustSexp("(AND 1 2)"), func(l *Leaf) (interface{}, error) {
+		return nil, failed
+	}, nil)
+	assert.Equal(t, failed, err)
+
+	_, err = Fold(mustSexp("(AND 1 2)"), func(l *Leaf) (interface{}, error) {
+		return l.Val, nil
+	}, func(g *Group, values []interface{}) (interface{}, error) {
+		return nil, failed
+	})
+	assert.Equal(t, failed, err)
+}
@@ -0,0 +1,145 @@
+package parse
+
+import (
+	"io/ioutil"
+)
+
+type evalNode struct {
+	parent int
+	and    bool
+	// size is the number of children, and count is how many of them are true
+	size  int
+	count int
+	value bool
+}
+
+// Evaluator keeps the result of every node in a tree, so that when a single
+// leaf changes only the nodes between it and the root are looked at again.
+// Every leaf starts out false.
+type Evaluator struct {
+	nodes    []evalNode
+	leaves   map[uint][]int
+	values   map[uint]bool
+	onChange func(bool)
+}
+
+// NewEvaluator will build an Evaluator for the tree. onChange is called with
+// the new result every time Set changes it, and can be nil.
+//	n, _ := Parse("1 AND (2 OR 3)")
+//	e, _ := NewEvaluator(n, func(r bool) { fmt.Println("now", r) })
+//	e.Set(1, true)
+//	e.Set(3, true)
+// Will print "now true" after 3 is set
+func NewEvaluator(n Node, onChange func(bool)) (*Evaluator, error) {
+	e := &Evaluator{
+		leaves:   map[uint][]int{},
+		values:   map[uint]bool{},
+		onChange: onChange,
+	}
+
+	// Flatten drops missing nodes, so check the tree the way Eval does first
+	if n != nil {
+		if err := n.Eval(ioutil.Discard); err != nil {
+			if serr, ok := err.(*SerializeError); ok {
+				return nil, &CompileError{
+					Op:     serr.Op,
+					Reason: serr.Reason,
+				}
+			}
+			return nil, err
+		}
+	}
+
+	if err := e.add(Flatten(n), -1); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// add puts the node and everything under it into the evaluator. With every
+// leaf false, every node starts out false too. The tree has already been
+// checked and flattened, so the only thing left to fail on is a nil tree.
+func (e *Evaluator) add(n Node, parent int) error {
+	i := len(e.nodes)
+	e.nodes = append(e.nodes, evalNode{parent: parent})
+
+	switch node := n.(type) {
+	case *Leaf:
+		e.leaves[node.Val] = append(e.leaves[node.Val], i)
+		return nil
+	case *Group:
+		e.nodes[i].and = node.Val == "AND"
+		e.nodes[i].size = len(node.Nodes)
+		for _, c := range node.Nodes {
+			if err := e.add(c, i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return &CompileError{
+		Reason: "nil node",
+	}
+}
+
+// Set will change the value of a leaf, updating every node that depends on it
+// and calling onChange if the result changed. Leaves that are not in the tree
+// are remembered but change nothing.
+func (e *Evaluator) Set(leaf uint, value bool) {
+	if e.values[leaf] == value {
+		return
+	}
+	e.values[leaf] = value
+
+	before := e.Result()
+	for _, i := range e.leaves[leaf] {
+		e.nodes[i].value = value
+		e.update(i)
+	}
+
+	if after := e.Result(); after != before && e.onChange != nil {
+		e.onChange(after)
+	}
+}
+
+// update walks up from a node that just changed, stopping at the first parent
+// whose value stays the same
+func (e *Evaluator) update(i int) {
+	for {
+		p := e.nodes[i].parent
+		if p < 0 {
+			return
+		}
+
+		parent := &e.nodes[p]
+		if e.nodes[i].value {
+			parent.count++
+		} else {
+			parent.count--
+		}
+
+		var value bool
+		if parent.and {
+			value = parent.count == parent.size
+		} else {
+			value = parent.count > 0
+		}
+
+		if value == parent.value {
+			return
+		}
+		parent.value = value
+		i = p
+	}
+}
+
+// Value is the current value of a leaf
+func (e *Evaluator) Value(leaf uint) bool {
+	return e.values[leaf]
+}
+
+// Result is the current value of the whole tree
+func (e *Evaluator) Result() bool {
+	return e.nodes[0].value
+}
@@ -0,0 +1,106 @@
+package parse
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluator(t *testing.T) {
+	assert := assert.New(t)
+	n, err := Parse("1 AND (2 OR 3)")
+	assert.NoError(err)
+
+	var changes []bool
+	e, err := NewEvaluator(n, func(r bool) {
+		changes = append(changes, r)
+	})
+	assert.NoError(err)
+	assert.False(e.Result())
+
+	e.Set(1, true)
+	assert.False(e.Result())
+	assert.Nil(changes)
+
+	e.Set(3, true)
+	assert.True(e.Result())
+	assert.Equal([]bool{true}, changes)
+
+	e.Set(2, true)
+	e.Set(3, false)
+	assert.True(e.Result())
+	assert.Equal([]bool{true}, changes, "Should not call back while the result stays the same")
+
+	e.Set(1, false)
+	assert.False(e.Result())
+	assert.Equal([]bool{true, false}, changes)
+
+	e.Set(9, true)
+	assert.True(e.Value(9))
+	assert.False(e.Value(3))
+	assert.Equal([]bool{true, false}, changes)
+}
+
+func TestEvaluatorRepeatedLeaves(t *testing.T) {
+	assert := assert.New(t)
+	n, err := Parse("1 AND 2 OR (1 AND 3)")
+	assert.NoError(err)
+
+	e, err := NewEvaluator(n, nil)
+	assert.NoError(err)
+
+	e.Set(1, true)
+	e.Set(3, true)
+	assert.True(e.Result())
+	e.Set(1, false)
+	assert.False(e.Result())
+}
+
+func TestEvaluatorRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 100; i++ {
+		n := randomTree(r, 5, 8)
+		values := map[uint]bool{}
+
+		calls := 0
+		e, err := NewEvaluator(n, func(bool) { calls++ })
+		assert.NoError(t, err)
+
+		last := false
+		expectedCalls := 0
+		for j := 0; j < 50; j++ {
+			leaf, value := uint(r.Intn(8)), r.Intn(2) == 0
+			values[leaf] = value
+			e.Set(leaf, value)
+
+			expected := Match(n, func(v uint) bool { return values[v] })
+			if expected != last {
+				expectedCalls++
+				last = expected
+			}
+			assert.Equal(t, expected, e.Result(), "%v with %v", n, values)
+		}
+		assert.Equal(t, expectedCalls, calls)
+	}
+}
+
+func TestEvaluatorErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewEvaluator(nil, nil)
+	assert.Equal(&CompileError{Reason: "nil node"}, err)
+
+	_, err = NewEvaluator(&Op{Left: &Leaf{1}, Val: "FOO", Right: &Leaf{2}}, nil)
+	assert.Equal(&CompileError{Op: "FOO", Reason: "bad operation"}, err)
+
+	_, err = NewEvaluator(&Op{Left: &Leaf{1}, Val: "AND"}, nil)
+	assert.Equal(&CompileError{Op: "AND", Reason: "nil right node"}, err)
+
+	_, err = NewEvaluator(&Group{Val: "OR", Nodes: []Node{&Leaf{1}, nil}}, nil)
+	assert.Equal(&CompileError{Op: "OR", Reason: "nil node at 1"}, err)
+
+	_, err = NewEvaluator(&Group{Val: "OR"}, nil)
+	assert.Equal(&CompileError{Op: "OR", Reason: "empty group"}, err)
+}
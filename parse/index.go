@@ -25,6 +25,22 @@ func (o *Op) Index(start int) Node {
 	}
 }
 
+// Index Group will index every node in the group
+func (g *Group) Index(start int) Node {
+
+	nodes := make([]Node, len(g.Nodes))
+	for i, n := range g.Nodes {
+		if n != nil {
+			nodes[i] = n.Index(start)
+		}
+	}
+
+	return &Group{
+		Val:   g.Val,
+		Nodes: nodes,
+	}
+}
+
 // Index Leaf will add start to the current value
 func (l *Leaf) Index(start int) Node {
 	return &Leaf{
@@ -70,6 +86,15 @@ func WalkLeaves(n Node, visit Visitor) Node {
 			Val:   node.Val,
 			Right: WalkLeaves(node.Right, visit),
 		}
+	case *Group:
+		nodes := make([]Node, len(node.Nodes))
+		for i, c := range node.Nodes {
+			nodes[i] = WalkLeaves(c, visit)
+		}
+		return &Group{
+			Val:   node.Val,
+			Nodes: nodes,
+		}
 	}
 	return n
 }
@@ -103,6 +103,30 @@ func TestIndex(t *testing.T) {
 				},
 			},
 		},
+		{
+			"Should reindex a group",
+			&Group{
+				Val: "OR",
+				Nodes: []Node{
+					&Leaf{1},
+					&Group{
+						Val:   "AND",
+						Nodes: []Node{&Leaf{2}, &Leaf{3}},
+					},
+				},
+			},
+			2,
+			&Group{
+				Val: "OR",
+				Nodes: []Node{
+					&Leaf{3},
+					&Group{
+						Val:   "AND",
+						Nodes: []Node{&Leaf{4}, &Leaf{5}},
+					},
+				},
+			},
+		},
 		{
 			"Should handle nil leafs",
 			&Op{
@@ -220,6 +244,17 @@ func TestSequence(t *testing.T) {
 				},
 			},
 		},
+		{
+			"Should sequence a group",
+			&Group{
+				Val:   "AND",
+				Nodes: []Node{&Leaf{9}, &Leaf{4}, &Leaf{7}},
+			},
+			&Group{
+				Val:   "AND",
+				Nodes: []Node{&Leaf{2}, &Leaf{0}, &Leaf{1}},
+			},
+		},
 		{
 			"Should handle nil leafs",
 			&Op{
@@ -0,0 +1,189 @@
+package parse
+
+import (
+	"fmt"
+)
+
+// Insert a leaf by value next to another leaf
+//	Insert(n, 3, "OR", 7)
+// Turns every leaf that has a value of 3 into (3 OR 7). It will ignore the
+// insert if the tree does not contain the target leaf, much like Remove.
+func Insert(n Node, target uint, op string, v uint) (Node, error) {
+	if !validOp(op) {
+		return nil, &EditError{
+			Op:     op,
+			Reason: "bad operation",
+		}
+	}
+
+	return WalkLeaves(n, initInsert(target, op, v)), nil
+}
+
+// InsertAfter adds a new leaf right after the target leaf. Every leaf above
+// the target is shifted up by one to make room for the new leaf, which will
+// have a value of target + 1.
+//	n, _ := Parse("1 AND 2 AND 3")
+//	n, _ = InsertAfter(n, 1, "OR")
+// Will yield a tree for "1 OR 2 AND 3 AND 4". Like Insert, it will return the
+// tree unchanged if it does not contain the target leaf.
+func InsertAfter(n Node, target uint, op string) (Node, error) {
+	if !validOp(op) {
+		return nil, &EditError{
+			Op:     op,
+			Reason: "bad operation",
+		}
+	}
+
+	if !Stats(n).Leaves.Has(int(target)) {
+		return n, nil
+	}
+
+	shifted, err := Shift(n, target+1, 1)
+	if err != nil {
+		return nil, err
+	}
+	return Insert(shifted, target, op, target+1)
+}
+
+// Wrap will find the subtree sub in n and replace it with an operation that
+// has sub on the left and with on the right. The subtree is matched by
+// identity, so it must be a node from n and not an equivalent copy.
+//	n, _ := Parse("1 AND (2 OR 3)")
+//	n, _ = Wrap(n, n.(*Op).Right, "AND", &Leaf{4})
+// Will yield a tree for "1 AND (2 OR 3 AND 4)"
+func Wrap(n Node, sub Node, op string, with Node) (Node, error) {
+	if !validOp(op) {
+		return nil, &EditError{
+			Op:     op,
+			Reason: "bad operation",
+		}
+	}
+
+	if with == nil {
+		return nil, &EditError{
+			Op:     op,
+			Reason: "nil node to wrap with",
+		}
+	}
+
+	w, found := wrap(n, sub, op, with)
+	if !found {
+		return nil, &EditError{
+			Op:     op,
+			Reason: fmt.Sprintf("%v is not in the tree", sub),
+		}
+	}
+
+	return w, nil
+}
+
+func wrap(n Node, sub Node, op string, with Node) (Node, bool) {
+	if n == nil {
+		return nil, false
+	}
+
+	if n == sub {
+		return &Op{
+			Left:  n,
+			Val:   op,
+			Right: with,
+		}, true
+	}
+
+	switch node := n.(type) {
+	case *Op:
+		if l, found := wrap(node.Left, sub, op, with); found {
+			return &Op{
+				Left:  l,
+				Val:   node.Val,
+				Right: node.Right,
+			}, true
+		}
+
+		if r, found := wrap(node.Right, sub, op, with); found {
+			return &Op{
+				Left:  node.Left,
+				Val:   node.Val,
+				Right: r,
+			}, true
+		}
+	case *Group:
+		for i, c := range node.Nodes {
+			if w, found := wrap(c, sub, op, with); found {
+				nodes := make([]Node, len(node.Nodes))
+				copy(nodes, node.Nodes)
+				nodes[i] = w
+				return &Group{
+					Val:   node.Val,
+					Nodes: nodes,
+				}, true
+			}
+		}
+	}
+
+	return n, false
+}
+
+// Shift will add by to every leaf that has a value at or above at. This is
+// the opposite of what happens to the condition numbers after a condition is
+// removed, and it is how room is made for a new leaf in the middle. It fails
+// without changing anything if a leaf would end up below 0 or past the
+// largest uint.
+//	Shift(n, 3, 1)
+// Turns "1 AND 3 OR 4" into "1 AND 4 OR 5"
+func Shift(n Node, at uint, by int) (Node, error) {
+	var bad *Leaf
+	WalkLeaves(n, func(c Node) Node {
+		l := c.(*Leaf)
+		if bad != nil || l.Val < at {
+			return c
+		}
+		if (by < 0 && uint(-by) > l.Val) || (by > 0 && uint(by) > ^uint(0)-l.Val) {
+			bad = l
+		}
+		return c
+	})
+
+	if bad != nil {
+		return nil, &EditError{
+			Reason: fmt.Sprintf("condition %d can't be shifted by %d", bad.Val, by),
+		}
+	}
+
+	return WalkLeaves(n, initShift(at, by)), nil
+}
+
+func initInsert(target uint, op string, v uint) Visitor {
+	return func(n Node) Node {
+		if l, ok := n.(*Leaf); ok && l.Val == target {
+			return &Op{
+				Left:  l,
+				Val:   op,
+				Right: &Leaf{v},
+			}
+		}
+		return n
+	}
+}
+
+func initShift(at uint, by int) Visitor {
+	return func(n Node) Node {
+		if l, ok := n.(*Leaf); ok && l.Val >= at {
+			if by < 0 {
+				return &Leaf{l.Val - uint(-by)}
+			}
+			return &Leaf{l.Val + uint(by)}
+		}
+		return n
+	}
+}
+
+// EditError holds information about why a tree could not be edited
+type EditError struct {
+	Op     string
+	Reason string
+}
+
+func (e *EditError) Error() string {
+	return fmt.Sprintf("Could not edit with operation '%s'. Reason: %s", e.Op, e.Reason)
+}
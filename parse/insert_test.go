@@ -0,0 +1,221 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsert(t *testing.T) {
+	cases := []struct {
+		desc     string
+		logic    string
+		target   uint
+		op       string
+		val      uint
+		expected string
+	}{
+		{
+			"Should not insert anything if not found",
+			"1 AND 2",
+			3,
+			"OR",
+			4,
+			"1 AND 2",
+		},
+		{
+			"Should insert next to a single leaf",
+			"1",
+			1,
+			"AND",
+			2,
+			"1 AND 2",
+		},
+		{
+			"Should insert next to a leaf on the right",
+			"1 AND 2 OR 3",
+			3,
+			"AND",
+			4,
+			"1 AND 2 OR (3 AND 4)",
+		},
+		{
+			"Should insert next to a leaf on the left without changing the meaning",
+			"1 AND 2 OR 3",
+			1,
+			"OR",
+			4,
+			"1 OR 4 AND 2 OR 3",
+		},
+		{
+			"Should insert next to the leaf in multiple places",
+			"1 OR (2 AND 1)",
+			1,
+			"AND",
+			3,
+			"1 AND 3 OR (2 AND (1 AND 3))",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			n, err := Parse(c.logic)
+			assert.NoError(err)
+
+			n, err = Insert(n, c.target, c.op, c.val)
+			assert.NoError(err)
+
+			var b strings.Builder
+			assert.NoError(n.Eval(&b))
+			assert.Equal(c.expected, b.String())
+		})
+	}
+}
+
+func TestInsertAfter(t *testing.T) {
+	cases := []struct {
+		desc     string
+		logic    string
+		target   uint
+		op       string
+		expected string
+	}{
+		{
+			"Should insert after the last leaf",
+			"1 AND 2 AND 3",
+			3,
+			"OR",
+			"1 AND 2 AND (3 OR 4)",
+		},
+		{
+			"Should insert in the middle and shift the leaves above",
+			"1 AND 2 AND 3",
+			1,
+			"OR",
+			"1 OR 2 AND 3 AND 4",
+		},
+		{
+			"Should not change anything if the target is not found",
+			"1 AND 3",
+			2,
+			"OR",
+			"1 AND 3",
+		},
+		{
+			"Should shift leaves that are out of order",
+			"4 AND (3 OR 1) AND 2",
+			2,
+			"OR",
+			"5 AND (4 OR 1) AND (2 OR 3)",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			n, err := Parse(c.logic)
+			assert.NoError(err)
+
+			n, err = InsertAfter(n, c.target, c.op)
+			assert.NoError(err)
+
+			var b strings.Builder
+			assert.NoError(n.Eval(&b))
+			assert.Equal(c.expected, b.String())
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	assert := assert.New(t)
+
+	n, err := Parse("1 AND (2 OR 3)")
+	assert.NoError(err)
+
+	w, err := Wrap(n, n.(*Op).Right, "AND", &Leaf{4})
+	assert.NoError(err)
+
+	var b strings.Builder
+	assert.NoError(w.Eval(&b))
+	assert.Equal("1 AND (2 OR 3 AND 4)", b.String())
+
+	w, err = Wrap(n, n, "OR", &Leaf{4})
+	assert.NoError(err)
+
+	b.Reset()
+	assert.NoError(w.Eval(&b))
+	assert.Equal("1 AND (2 OR 3) OR 4", b.String())
+
+	// The original tree should be untouched
+	b.Reset()
+	assert.NoError(n.Eval(&b))
+	assert.Equal("1 AND (2 OR 3)", b.String())
+}
+
+func TestEditErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	n, err := Parse("1 AND 2")
+	assert.NoError(err)
+
+	_, err = Insert(n, 1, "FOO", 3)
+	assert.Equal(&EditError{Op: "FOO", Reason: "bad operation"}, err)
+
+	_, err = InsertAfter(n, 1, "")
+	assert.Equal(&EditError{Op: "", Reason: "bad operation"}, err)
+
+	_, err = Wrap(n, &Leaf{1}, "AND", &Leaf{3})
+	assert.Equal(&EditError{Op: "AND", Reason: "1 is not in the tree"}, err)
+
+	_, err = Wrap(n, n, "AND", nil)
+	assert.Equal(&EditError{Op: "AND", Reason: "nil node to wrap with"}, err)
+}
+
+func TestShift(t *testing.T) {
+	assert := assert.New(t)
+
+	n, err := Parse("1 AND 3 OR 4")
+	assert.NoError(err)
+
+	s, err := Shift(n, 3, 1)
+	assert.NoError(err)
+
+	var b strings.Builder
+	assert.NoError(s.Eval(&b))
+	assert.Equal("1 AND 4 OR 5", b.String())
+
+	s, err = Shift(n, 3, -1)
+	assert.NoError(err)
+
+	b.Reset()
+	assert.NoError(s.Eval(&b))
+	assert.Equal("1 AND 2 OR 3", b.String())
+
+	s, err = Shift(n, 3, -3)
+	assert.NoError(err)
+
+	b.Reset()
+	assert.NoError(s.Eval(&b))
+	assert.Equal("1 AND 0 OR 1", b.String())
+}
+
+func TestShiftErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	n, err := Parse("1 AND 3")
+	assert.NoError(err)
+
+	_, err = Shift(n, 0, -2)
+	assert.Equal(&EditError{Reason: "condition 1 can't be shifted by -2"}, err)
+
+	// Leaves below at are left alone, so they can't go below 0
+	s, err := Shift(n, 2, -2)
+	assert.NoError(err)
+	assert.Equal("(AND 1 1)", Sexp(s))
+
+	_, err = Shift(&Leaf{^uint(0)}, 0, 1)
+	assert.Equal(&EditError{Reason: fmt.Sprintf("condition %d can't be shifted by 1", ^uint(0))}, err)
+}
@@ -0,0 +1,76 @@
+package parse
+
+import (
+	"unicode"
+)
+
+// word is a number, an operation or a parenthesis in logic, along with where
+// it starts
+type word struct {
+	text   string
+	pos    int
+	number bool
+}
+
+func (w word) paren() bool {
+	return w.text == "(" || w.text == ")"
+}
+
+// lex splits logic into numbers, operations and parentheses, failing the
+// same way Parse does on anything else. It doesn't check how they are put
+// together.
+func lex(logic string) ([]word, error) {
+	var tokens []word
+	start := -1
+
+	for i, r := range logic + " " {
+		switch {
+		case unicode.IsNumber(r):
+			if start >= 0 && !unicode.IsNumber(rune(logic[start])) {
+				return nil, &ParseError{
+					Position: i,
+					Logic:    logic,
+					Reason:   "unexpected number",
+				}
+			}
+			if start < 0 {
+				start = i
+			}
+			continue
+		case unicode.IsLetter(r):
+			if start >= 0 && unicode.IsNumber(rune(logic[start])) {
+				return nil, &ParseError{
+					Position: i,
+					Logic:    logic,
+					Reason:   "unexpected character",
+				}
+			}
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+
+		if start >= 0 {
+			tokens = append(tokens, word{
+				text:   logic[start:i],
+				pos:    start,
+				number: unicode.IsNumber(rune(logic[start])),
+			})
+			start = -1
+		}
+
+		switch {
+		case r == '(' || r == ')':
+			tokens = append(tokens, word{text: string(r), pos: i})
+		case !unicode.IsSpace(r):
+			return nil, &ParseError{
+				Position: i,
+				Logic:    logic,
+				Reason:   "general error",
+			}
+		}
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,112 @@
+package parse
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// Logic holds a tree so it can be read from and written to anywhere that
+// takes logic strings. It implements sql.Scanner and driver.Valuer for
+// database columns, encoding.TextMarshaler and encoding.TextUnmarshaler for
+// config files and JSON, and flag.Value for command lines. A nil Node stands
+// for NULL, and so does empty logic.
+//	var l parse.Logic
+//	db.QueryRow("SELECT logic FROM filters WHERE id = $1", id).Scan(&l)
+// Will parse the logic in the column into l.Node
+type Logic struct {
+	Node Node
+}
+
+// Scan will parse a string or []byte from a database column. A NULL column
+// leaves a nil Node.
+func (l *Logic) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		l.Node = nil
+		return nil
+	case string:
+		return l.Set(v)
+	case []byte:
+		return l.Set(string(v))
+	}
+	return &ScanError{
+		Type:   fmt.Sprintf("%T", src),
+		Reason: "only strings, []byte and NULL can be scanned",
+	}
+}
+
+// Value will serialize the tree with Eval for a database column. A nil Node
+// is written as NULL.
+func (l Logic) Value() (driver.Value, error) {
+	if l.Node == nil {
+		return nil, nil
+	}
+
+	s, err := l.eval()
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// MarshalText will serialize the tree with Eval. A nil Node is written as
+// empty logic.
+func (l Logic) MarshalText() ([]byte, error) {
+	if l.Node == nil {
+		return []byte{}, nil
+	}
+
+	s, err := l.eval()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText will parse the logic
+func (l *Logic) UnmarshalText(text []byte) error {
+	return l.Set(string(text))
+}
+
+// String will serialize the tree with Eval, returning empty logic for a nil
+// Node or a tree that can't be serialized
+func (l Logic) String() string {
+	if l.Node == nil {
+		return ""
+	}
+
+	s, err := l.eval()
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+// Set will parse the logic, leaving the Node as it was if it fails
+func (l *Logic) Set(logic string) error {
+	n, err := Parse(logic)
+	if err != nil {
+		return err
+	}
+	l.Node = n
+	return nil
+}
+
+func (l Logic) eval() (string, error) {
+	var b strings.Builder
+	if err := l.Node.Eval(&b); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// ScanError is returned when Scan is handed a value it can't read logic from
+type ScanError struct {
+	Type   string
+	Reason string
+}
+
+func (e *ScanError) Error() string {
+	return fmt.Sprintf("Could not scan %s into Logic. Reason: %s", e.Type, e.Reason)
+}
@@ -0,0 +1,146 @@
+package parse
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ sql.Scanner              = &Logic{}
+	_ driver.Valuer            = Logic{}
+	_ encoding.TextMarshaler   = Logic{}
+	_ encoding.TextUnmarshaler = &Logic{}
+	_ flag.Value               = &Logic{}
+)
+
+func TestLogicScan(t *testing.T) {
+	cases := []struct {
+		desc     string
+		src      interface{}
+		expected string
+		err      error
+	}{
+		{
+			"Should scan a string",
+			"1 AND (2 OR 3)",
+			"(AND 1 (OR 2 3))",
+			nil,
+		},
+		{
+			"Should scan bytes",
+			[]byte("1 OR 2"),
+			"(OR 1 2)",
+			nil,
+		},
+		{
+			"Should scan NULL",
+			nil,
+			"nil",
+			nil,
+		},
+		{
+			"Should scan empty logic",
+			"",
+			"nil",
+			nil,
+		},
+		{
+			"Should fail with bad logic",
+			"1 AND",
+			"(AND 4 5)",
+			&ParseError{Position: 2, Logic: "1 AND", Reason: "unexpected operation"},
+		},
+		{
+			"Should fail with a number",
+			int64(1),
+			"(AND 4 5)",
+			&ScanError{Type: "int64", Reason: "only strings, []byte and NULL can be scanned"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			l := Logic{mustSexp("(AND 4 5)")}
+			err := l.Scan(c.src)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, Sexp(l.Node))
+		})
+	}
+}
+
+func TestLogicValue(t *testing.T) {
+	cases := []struct {
+		desc     string
+		tree     Node
+		expected driver.Value
+		err      error
+	}{
+		{
+			"Should write the logic",
+			mustSexp("(OR (AND 1 2) 3)"),
+			"1 AND 2 OR 3",
+			nil,
+		},
+		{
+			"Should write NULL",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"Should fail with a tree that can't be serialized",
+			mustSexp("(AND 1 nil)"),
+			nil,
+			&SerializeError{Op: "AND", Reason: "nil right node"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			v, err := Logic{c.tree}.Value()
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, v)
+		})
+	}
+}
+
+func TestLogicText(t *testing.T) {
+	type config struct {
+		Filter Logic `json:"filter"`
+	}
+
+	var c config
+	assert.NoError(t, json.Unmarshal([]byte(`{"filter": "1 OR 2 AND 3"}`), &c))
+	assert.Equal(t, "(AND (OR 1 2) 3)", Sexp(c.Filter.Node))
+
+	data, err := json.Marshal(c)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"filter":"1 OR 2 AND 3"}`, string(data))
+
+	err = json.Unmarshal([]byte(`{"filter": "1 2"}`), &c)
+	assert.Error(t, err)
+
+	data, err = Logic{}.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "", string(data))
+}
+
+func TestLogicFlag(t *testing.T) {
+	var l Logic
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&l, "logic", "condition logic")
+
+	assert.NoError(t, fs.Parse([]string{"-logic", "1 AND (2 OR 3)"}))
+	assert.Equal(t, "1 AND (2 OR 3)", l.String())
+
+	assert.Error(t, l.Set("1 AND"))
+	assert.Equal(t, "1 AND (2 OR 3)", l.String())
+
+	assert.Equal(t, "", Logic{}.String())
+}
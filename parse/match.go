@@ -0,0 +1,28 @@
+package parse
+
+// Match will evaluate the tree as a boolean expression, asking value for the
+// value of each leaf it needs. Nodes are evaluated left to right and stop as
+// soon as the result is known, so value may not be asked about every leaf.
+//	n, _ := Parse("1 OR 2 AND 3")
+//	Match(n, func(v uint) bool { return v != 3 })
+// Will return false, since (1 OR 2) AND 3 needs 3 to be true
+func Match(n Node, value func(uint) bool) bool {
+	switch node := n.(type) {
+	case *Leaf:
+		return value(node.Val)
+	case *Op:
+		if node.Val == "AND" {
+			return Match(node.Left, value) && Match(node.Right, value)
+		}
+		return Match(node.Left, value) || Match(node.Right, value)
+	case *Group:
+		and := node.Val == "AND"
+		for _, c := range node.Nodes {
+			if Match(c, value) != and {
+				return !and
+			}
+		}
+		return and
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		desc     string
+		logic    string
+		values   map[uint]bool
+		expected bool
+		asked    []uint
+	}{
+		{
+			"Should match a single leaf",
+			"1",
+			map[uint]bool{1: true},
+			true,
+			[]uint{1},
+		},
+		{
+			"Should read left to right",
+			"1 OR 2 AND 3",
+			map[uint]bool{1: true, 2: false, 3: false},
+			false,
+			[]uint{1, 3},
+		},
+		{
+			"Should stop at the first false AND",
+			"1 AND 2 AND 3",
+			map[uint]bool{1: true, 2: false, 3: true},
+			false,
+			[]uint{1, 2},
+		},
+		{
+			"Should match parens",
+			"1 AND (2 OR 3)",
+			map[uint]bool{1: true, 2: false, 3: true},
+			true,
+			[]uint{1, 2, 3},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			n, err := Parse(c.logic)
+			assert.NoError(err)
+
+			for _, tree := range []Node{n, Flatten(n)} {
+				var asked []uint
+				actual := Match(tree, func(v uint) bool {
+					asked = append(asked, v)
+					return c.values[v]
+				})
+				assert.Equal(c.expected, actual)
+				assert.Equal(c.asked, asked)
+			}
+		})
+	}
+}
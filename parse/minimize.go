@@ -0,0 +1,259 @@
+package parse
+
+import (
+	"io/ioutil"
+
+	"golang.org/x/tools/container/intsets"
+)
+
+// Limits on exact minimization. Past these Minimize only does the cheap
+// simplifications.
+const (
+	// MaxExactLeaves is the most distinct leaves Minimize will expand
+	MaxExactLeaves = 16
+	// MaxExactTerms is the most terms Minimize will keep while expanding
+	MaxExactTerms = 1024
+)
+
+// Minimize will find a smaller tree that means the same thing.
+//
+// Without NOT, the minimal sum of products is just the smallest sets of
+// leaves that make the tree true, so for up to MaxExactLeaves leaves it works
+// those out exactly, along with the minimal product of sums, and then factors
+// out shared leaves to keep it readable. The smallest of those and the
+// simplified original tree wins. Bigger trees only get the simplified
+// original, which drops duplicate nodes and nodes absorbed by their
+// neighbors, like the (1 OR 2) in "1 AND (1 OR 2)". If nothing has fewer
+// leaves than the original tree, the original is kept as it is written, so
+// logic that is already minimal doesn't get its conditions reordered. A tree
+// that Eval can't serialize, like one with a missing node, is returned as it
+// is.
+//	n, _ := Parse("1 AND 2 OR (1 AND 3) OR (1 AND 2 AND 4)")
+//	Minimize(n)
+// Will yield a tree for "1 AND (2 OR 3)"
+func Minimize(n Node) Node {
+	if n == nil {
+		return nil
+	}
+
+	if err := n.Eval(ioutil.Discard); err != nil {
+		return n
+	}
+
+	best := simplify(Canonicalize(n))
+
+	leafs := &intsets.Sparse{}
+	WalkLeaves(n, initAddSet(leafs))
+
+	if leafs.Len() <= MaxExactLeaves {
+		if sop, ok := expand(n, "OR"); ok {
+			best = smaller(best, factor(sop, "OR", "AND"))
+		}
+		if pos, ok := expand(n, "AND"); ok {
+			best = smaller(best, factor(pos, "AND", "OR"))
+		}
+	}
+
+	if Stats(best).LeafCount >= Stats(n).LeafCount {
+		return Binarize(n)
+	}
+
+	return Binarize(Canonicalize(best))
+}
+
+// smaller picks the tree with fewer leaves, and then the shallower one,
+// keeping a if they are the same
+func smaller(a Node, b Node) Node {
+	sa, sb := Stats(a), Stats(b)
+	if sb.LeafCount < sa.LeafCount || (sb.LeafCount == sa.LeafCount && sb.Depth < sa.Depth) {
+		return b
+	}
+	return a
+}
+
+// expand turns the tree into sets of leaves joined by outer, with the leaves
+// in each set joined by the other operation. With OR that is the sum of
+// products, with AND the product of sums. Sets that hold another set are
+// dropped, since the smaller set already covers them. It gives up if there
+// are ever more than MaxExactTerms sets.
+func expand(n Node, outer string) ([]*intsets.Sparse, bool) {
+	var children []Node
+	var op string
+
+	switch node := n.(type) {
+	case *Leaf:
+		s := &intsets.Sparse{}
+		s.Insert(int(node.Val))
+		return []*intsets.Sparse{s}, true
+	case *Op:
+		op = node.Val
+		children = []Node{node.Left, node.Right}
+	case *Group:
+		op = node.Val
+		children = node.Nodes
+	default:
+		return nil, false
+	}
+
+	var sets []*intsets.Sparse
+	for i, c := range children {
+		cs, ok := expand(c, outer)
+		if !ok {
+			return nil, false
+		}
+
+		if i == 0 {
+			sets = cs
+		} else if op == outer {
+			sets = append(sets, cs...)
+		} else {
+			// Distribute, pairing every set so far with every set of the child
+			product := make([]*intsets.Sparse, 0, len(sets)*len(cs))
+			for _, a := range sets {
+				for _, b := range cs {
+					s := &intsets.Sparse{}
+					s.Union(a, b)
+					product = append(product, s)
+				}
+			}
+			sets = product
+		}
+
+		sets = absorb(sets)
+		if len(sets) > MaxExactTerms {
+			return nil, false
+		}
+	}
+
+	return sets, true
+}
+
+// absorb drops duplicate sets and any set that holds another one
+func absorb(sets []*intsets.Sparse) []*intsets.Sparse {
+	kept := make([]*intsets.Sparse, 0, len(sets))
+	for i, s := range sets {
+		keep := true
+		for j, t := range sets {
+			if i == j || !t.SubsetOf(s) {
+				continue
+			}
+			// Of two equal sets keep only the first
+			if !s.SubsetOf(t) || j < i {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+// factor builds a tree out of sets, pulling out the leaf shared by the most
+// sets over and over, so "1 AND 2 OR (1 AND 3)" becomes "1 AND (2 OR 3)"
+func factor(sets []*intsets.Sparse, outer string, inner string) Node {
+	if len(sets) == 1 {
+		return setNode(sets[0], inner)
+	}
+
+	counts := map[int]int{}
+	best, most := 0, 0
+	for _, s := range sets {
+		for _, v := range s.AppendTo(nil) {
+			counts[v]++
+			if counts[v] > most || (counts[v] == most && v < best) {
+				best, most = v, counts[v]
+			}
+		}
+	}
+
+	if most < 2 {
+		nodes := make([]Node, len(sets))
+		for i, s := range sets {
+			nodes[i] = setNode(s, inner)
+		}
+		return flatGroup(outer, nodes...)
+	}
+
+	var with, without []*intsets.Sparse
+	for _, s := range sets {
+		if s.Has(best) {
+			rest := &intsets.Sparse{}
+			rest.Copy(s)
+			rest.Remove(best)
+			with = append(with, rest)
+		} else {
+			without = append(without, s)
+		}
+	}
+
+	shared := flatGroup(inner, &Leaf{uint(best)}, factor(with, outer, inner))
+	if len(without) == 0 {
+		return shared
+	}
+	return flatGroup(outer, shared, factor(without, outer, inner))
+}
+
+func setNode(s *intsets.Sparse, op string) Node {
+	vals := s.AppendTo(nil)
+	nodes := make([]Node, len(vals))
+	for i, v := range vals {
+		nodes[i] = &Leaf{uint(v)}
+	}
+	return flatGroup(op, nodes...)
+}
+
+// simplify will drop duplicate nodes and absorbed nodes from a canonical
+// tree, working from the leaves up
+func simplify(n Node) Node {
+	g, ok := n.(*Group)
+	if !ok {
+		return n
+	}
+
+	nodes := make([]Node, len(g.Nodes))
+	for i, c := range g.Nodes {
+		nodes[i] = simplify(c)
+	}
+	flat, ok := flatGroup(g.Val, nodes...).(*Group)
+	if !ok {
+		return flat
+	}
+	nodes = flat.Nodes
+
+	// Drop duplicates, which are next to each other once canonical
+	kept := make([]Node, 0, len(nodes))
+	for _, c := range canonical(&Group{Val: g.Val, Nodes: nodes}).(*Group).Nodes {
+		if len(kept) > 0 && Equal(kept[len(kept)-1], c) {
+			continue
+		}
+		kept = append(kept, c)
+	}
+
+	// Drop any group of the other operation that holds one of its neighbors,
+	// since the neighbor already decides it
+	absorbed := make([]Node, 0, len(kept))
+	for i, c := range kept {
+		if inner, ok := c.(*Group); ok && inner.Val != g.Val && holdsAny(inner, kept, i) {
+			continue
+		}
+		absorbed = append(absorbed, c)
+	}
+
+	return flatGroup(g.Val, absorbed...)
+}
+
+func holdsAny(g *Group, neighbors []Node, self int) bool {
+	for j, other := range neighbors {
+		if j == self {
+			continue
+		}
+		for _, c := range g.Nodes {
+			if Equal(c, other) {
+				return true
+			}
+		}
+	}
+	return false
+}
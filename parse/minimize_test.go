@@ -0,0 +1,170 @@
+package parse
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinimize(t *testing.T) {
+	cases := []struct {
+		desc     string
+		logic    string
+		expected string
+	}{
+		{
+			"Should leave a single leaf alone",
+			"1",
+			"1",
+		},
+		{
+			"Should drop duplicates",
+			"1 AND 1 AND 2",
+			"1 AND 2",
+		},
+		{
+			"Should drop absorbed groups",
+			"1 AND (1 OR 2)",
+			"1",
+		},
+		{
+			"Should factor out shared leaves",
+			"1 AND 2 OR (1 AND 3) OR (1 AND 2 AND 4)",
+			"1 AND (2 OR 3)",
+		},
+		{
+			"Should find the product of sums",
+			"1 AND 2 OR (1 AND 3) OR (4 AND 2) OR (4 AND 3)",
+			"1 OR 4 AND (2 OR 3)",
+		},
+		{
+			"Should keep logic that is already minimal as it is written",
+			"1 OR 2 AND 3",
+			"1 OR 2 AND 3",
+		},
+		{
+			"Should keep the order of a minimal group",
+			"3 AND (2 OR 1)",
+			"3 AND (2 OR 1)",
+		},
+		{
+			"Should simplify deep redundancy",
+			"1 OR (5 AND (7 OR 8)) AND (3 AND 2 OR (5 AND 1) OR 4) OR 1",
+			"1 OR (5 AND (4 OR (2 AND 3)) AND (7 OR 8))",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			n, err := Parse(c.logic)
+			assert.NoError(err)
+
+			m := Minimize(n)
+
+			var b strings.Builder
+			assert.NoError(m.Eval(&b))
+			assert.Equal(c.expected, b.String())
+			assertSame(assert, n, m)
+		})
+	}
+}
+
+func TestMinimizeRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		n := randomTree(r, 5, 6)
+		m := Minimize(n)
+
+		assertSame(assert.New(t), n, m)
+		assert.True(t, Stats(m).LeafCount <= Stats(n).LeafCount, "%v should not be bigger than %v", m, n)
+	}
+}
+
+func TestMinimizeLarge(t *testing.T) {
+	assert := assert.New(t)
+
+	// Too many leaves to expand, but the duplicate should still go
+	var b strings.Builder
+	for i := 0; i < 40; i += 2 {
+		fmt.Fprintf(&b, "(%d OR %d) AND ", i, i+1)
+	}
+	b.WriteString("(0 OR 1)")
+
+	n, err := Parse(b.String())
+	assert.NoError(err)
+
+	m := Minimize(n)
+	assert.Equal(40, Stats(m).LeafCount)
+	assertSame(assert, n, m)
+	assert.Nil(Minimize(nil))
+}
+
+func TestMinimizeMissingNode(t *testing.T) {
+	cases := []struct {
+		desc string
+		tree Node
+	}{
+		{
+			"Should keep an op with a missing node",
+			&Op{Left: &Leaf{1}, Val: "OR"},
+		},
+		{
+			"Should keep a group with a missing node",
+			&Group{Val: "AND", Nodes: []Node{&Leaf{1}, nil, &Leaf{2}}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert.Equal(t, c.tree, Minimize(c.tree))
+		})
+	}
+}
+
+// assertSame checks that two trees match for random assignments of their
+// leaves, or every assignment if there are few enough
+func assertSame(assert *assert.Assertions, a Node, b Node) {
+	leaves := Stats(a).Leaves.AppendTo(nil)
+	r := rand.New(rand.NewSource(2))
+
+	tries := 1 << uint(len(leaves))
+	if len(leaves) > 10 {
+		tries = 1024
+	}
+
+	for i := 0; i < tries; i++ {
+		values := map[uint]bool{}
+		for j, v := range leaves {
+			if len(leaves) > 10 {
+				values[uint(v)] = r.Intn(2) == 0
+			} else {
+				values[uint(v)] = i&(1<<uint(j)) != 0
+			}
+		}
+		value := func(v uint) bool { return values[v] }
+		if Match(a, value) != Match(b, value) {
+			assert.Fail(fmt.Sprintf("%v and %v do not match for %v", a, b, values))
+			return
+		}
+	}
+}
+
+func randomTree(r *rand.Rand, depth int, leaves int) Node {
+	if depth == 0 || r.Intn(3) == 0 {
+		return &Leaf{uint(r.Intn(leaves))}
+	}
+	op := "AND"
+	if r.Intn(2) == 0 {
+		op = "OR"
+	}
+	return &Op{
+		Left:  randomTree(r, depth-1, leaves),
+		Val:   op,
+		Right: randomTree(r, depth-1, leaves),
+	}
+}
@@ -0,0 +1,260 @@
+package parse
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// ParsePrefix will parse logic written in prefix notation, with every
+// operation before its two nodes. There are no parentheses and no question of
+// precedence. The tree is the same one Parse builds for the same logic.
+//	ParsePrefix("AND 1 OR 2 3")
+// Will yield the same tree as Parse("1 AND (2 OR 3)")
+func ParsePrefix(logic string) (Node, error) {
+	tokens, err := notationTokens(logic)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	var i int
+	var prefix func() (Node, error)
+	prefix = func() (Node, error) {
+		t := tokens[i]
+		i++
+
+		if t.number {
+			return leaf(logic, t)
+		}
+
+		op, err := operation(logic, t)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(tokens)-i < 2 {
+			// Not enough tokens left for both nodes
+			return nil, &ParseError{
+				Position: t.pos,
+				Logic:    logic,
+				Reason:   "unexpected operation",
+			}
+		}
+
+		if op.Left, err = prefix(); err != nil {
+			return nil, err
+		}
+
+		if i == len(tokens) {
+			return nil, &ParseError{
+				Position: t.pos,
+				Logic:    logic,
+				Reason:   "unexpected operation",
+			}
+		}
+
+		if op.Right, err = prefix(); err != nil {
+			return nil, err
+		}
+
+		return op, nil
+	}
+
+	n, err := prefix()
+	if err != nil {
+		return nil, err
+	}
+
+	if i < len(tokens) {
+		return nil, unexpected(logic, tokens[i])
+	}
+
+	return n, nil
+}
+
+// ParsePostfix will parse logic written in postfix notation, with every
+// operation after its two nodes. There are no parentheses and no question of
+// precedence. The tree is the same one Parse builds for the same logic.
+//	ParsePostfix("1 2 3 OR AND")
+// Will yield the same tree as Parse("1 AND (2 OR 3)")
+func ParsePostfix(logic string) (Node, error) {
+	tokens, err := notationTokens(logic)
+	if err != nil {
+		return nil, err
+	}
+
+	var stack []Node
+	var starts []word
+	for _, t := range tokens {
+		if t.number {
+			l, err := leaf(logic, t)
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, l)
+			starts = append(starts, t)
+			continue
+		}
+
+		op, err := operation(logic, t)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(stack) < 2 {
+			return nil, &ParseError{
+				Position: t.pos,
+				Logic:    logic,
+				Reason:   "unexpected operation",
+			}
+		}
+
+		op.Left, op.Right = stack[len(stack)-2], stack[len(stack)-1]
+		stack = append(stack[:len(stack)-2], op)
+		starts = starts[:len(starts)-1]
+	}
+
+	if len(stack) > 1 {
+		// The second node on the stack is the first one nothing used
+		return nil, unexpected(logic, starts[1])
+	}
+
+	if len(stack) == 0 {
+		return nil, nil
+	}
+
+	return stack[0], nil
+}
+
+// notationTokens splits the logic into numbers and operations, failing on
+// parentheses since neither notation uses them
+func notationTokens(logic string) ([]word, error) {
+	tokens, err := lex(logic)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range tokens {
+		if t.paren() {
+			return nil, &ParseError{
+				Position: t.pos,
+				Logic:    logic,
+				Reason:   "general error",
+			}
+		}
+	}
+	return tokens, nil
+}
+
+func leaf(logic string, t word) (Node, error) {
+	v, err := strconv.ParseUint(t.text, 10, 0)
+	if err != nil {
+		return nil, &ParseError{
+			Position: t.pos + len(t.text),
+			Logic:    logic,
+			Reason:   fmt.Sprintf("%s not an unsigned int", t.text),
+		}
+	}
+	return &Leaf{uint(v)}, nil
+}
+
+func operation(logic string, t word) (*Op, error) {
+	if !validOp(t.text) {
+		return nil, &ParseError{
+			Position: t.pos,
+			Logic:    logic,
+			Reason:   fmt.Sprintf("%s is an unacceptable operation", t.text),
+		}
+	}
+	return &Op{Val: t.text}, nil
+}
+
+// unexpected fails on a token that nothing uses. Like Parse, it points at the
+// start of an operation but at the end of a leaf.
+func unexpected(logic string, t word) error {
+	reason := "unexpected operation"
+	pos := t.pos
+	if t.number {
+		reason = "unexpected leaf"
+		pos += len(t.text)
+	}
+	return &ParseError{
+		Position: pos,
+		Logic:    logic,
+		Reason:   reason,
+	}
+}
+
+// EvalPrefix will write the tree in prefix notation. Groups are written as
+// the chain of Ops Binarize would turn them into.
+//	n, _ := Parse("1 AND (2 OR 3)")
+//	EvalPrefix(n, w)
+// Will write "AND 1 OR 2 3"
+func EvalPrefix(n Node, w io.Writer) error {
+	return evalNotation(n, w, true)
+}
+
+// EvalPostfix will write the tree in postfix notation. Groups are written as
+// the chain of Ops Binarize would turn them into.
+//	n, _ := Parse("1 AND (2 OR 3)")
+//	EvalPostfix(n, w)
+// Will write "1 2 3 OR AND"
+func EvalPostfix(n Node, w io.Writer) error {
+	return evalNotation(n, w, false)
+}
+
+func evalNotation(n Node, w io.Writer, prefix bool) error {
+	if n == nil {
+		return nil
+	}
+
+	if err := n.Eval(ioutil.Discard); err != nil {
+		return err
+	}
+
+	first := true
+	emit := func(s string) error {
+		if !first {
+			s = " " + s
+		}
+		first = false
+		_, err := io.WriteString(w, s)
+		return err
+	}
+
+	var write func(Node) error
+	write = func(n Node) error {
+		op, ok := n.(*Op)
+		if !ok {
+			var b strings.Builder
+			if err := n.Eval(&b); err != nil {
+				return err
+			}
+			return emit(b.String())
+		}
+
+		if prefix {
+			if err := emit(op.Val); err != nil {
+				return err
+			}
+		}
+		if err := write(op.Left); err != nil {
+			return err
+		}
+		if err := write(op.Right); err != nil {
+			return err
+		}
+		if !prefix {
+			return emit(op.Val)
+		}
+		return nil
+	}
+
+	return write(Binarize(n))
+}
@@ -0,0 +1,247 @@
+package parse
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotation(t *testing.T) {
+	cases := []struct {
+		desc    string
+		logic   string
+		prefix  string
+		postfix string
+	}{
+		{
+			"Should handle a single leaf",
+			"1",
+			"1",
+			"1",
+		},
+		{
+			"Should handle a single operation",
+			"1 AND 2",
+			"AND 1 2",
+			"1 2 AND",
+		},
+		{
+			"Should handle a right hand group",
+			"1 AND (2 OR 3)",
+			"AND 1 OR 2 3",
+			"1 2 3 OR AND",
+		},
+		{
+			"Should handle left to right logic",
+			"1 OR 2 AND 3",
+			"AND OR 1 2 3",
+			"1 2 OR 3 AND",
+		},
+		{
+			"Should handle groups on both sides",
+			"1 AND 2 OR (3 AND 4)",
+			"OR AND 1 2 AND 3 4",
+			"1 2 AND 3 4 AND OR",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			expected, err := Parse(c.logic)
+			assert.NoError(t, err)
+
+			prefix, err := ParsePrefix(c.prefix)
+			assert.NoError(t, err)
+			assert.True(t, Equal(expected, prefix))
+
+			postfix, err := ParsePostfix(c.postfix)
+			assert.NoError(t, err)
+			assert.True(t, Equal(expected, postfix))
+
+			var b strings.Builder
+			assert.NoError(t, EvalPrefix(expected, &b))
+			assert.Equal(t, c.prefix, b.String())
+
+			b.Reset()
+			assert.NoError(t, EvalPostfix(expected, &b))
+			assert.Equal(t, c.postfix, b.String())
+		})
+	}
+}
+
+func TestNotationGroups(t *testing.T) {
+	g := &Group{
+		Val: "AND",
+		Nodes: []Node{
+			&Leaf{1},
+			&Group{Val: "OR", Nodes: []Node{&Leaf{2}, &Leaf{3}, &Leaf{4}}},
+			&Leaf{5},
+		},
+	}
+
+	var b strings.Builder
+	assert.NoError(t, EvalPrefix(g, &b))
+	assert.Equal(t, "AND AND 1 OR OR 2 3 4 5", b.String())
+
+	b.Reset()
+	assert.NoError(t, EvalPostfix(g, &b))
+	assert.Equal(t, "1 2 3 OR 4 OR AND 5 AND", b.String())
+}
+
+func TestNotationRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	for i := 0; i < 200; i++ {
+		n := randomTree(r, 5, 8)
+
+		var b strings.Builder
+		assert.NoError(t, EvalPrefix(n, &b))
+		prefix, err := ParsePrefix(b.String())
+		assert.NoError(t, err)
+		assert.True(t, Equal(n, prefix), b.String())
+
+		b.Reset()
+		assert.NoError(t, EvalPostfix(n, &b))
+		postfix, err := ParsePostfix(b.String())
+		assert.NoError(t, err)
+		assert.True(t, Equal(n, postfix), b.String())
+	}
+}
+
+func TestNotationEmpty(t *testing.T) {
+	n, err := ParsePrefix(" ")
+	assert.NoError(t, err)
+	assert.Nil(t, n)
+
+	n, err = ParsePostfix("")
+	assert.NoError(t, err)
+	assert.Nil(t, n)
+
+	var b strings.Builder
+	assert.NoError(t, EvalPrefix(nil, &b))
+	assert.NoError(t, EvalPostfix(nil, &b))
+	assert.Equal(t, "", b.String())
+}
+
+func TestNotationErrors(t *testing.T) {
+	cases := []struct {
+		desc    string
+		logic   string
+		prefix  *ParseError
+		postfix *ParseError
+	}{
+		{
+			"Should fail with only an operation",
+			"AND",
+			&ParseError{Position: 0, Logic: "AND", Reason: "unexpected operation"},
+			&ParseError{Position: 0, Logic: "AND", Reason: "unexpected operation"},
+		},
+		{
+			"Should fail with an unacceptable operation",
+			"1 2 FOO",
+			&ParseError{Position: 3, Logic: "1 2 FOO", Reason: "unexpected leaf"},
+			&ParseError{Position: 4, Logic: "1 2 FOO", Reason: "FOO is an unacceptable operation"},
+		},
+		{
+			"Should fail with a bad character",
+			"AND 1 (2)",
+			&ParseError{Position: 6, Logic: "AND 1 (2)", Reason: "general error"},
+			&ParseError{Position: 6, Logic: "AND 1 (2)", Reason: "general error"},
+		},
+		{
+			"Should fail with an unexpected number",
+			"AN132D 1 2",
+			&ParseError{Position: 2, Logic: "AN132D 1 2", Reason: "unexpected number"},
+			&ParseError{Position: 2, Logic: "AN132D 1 2", Reason: "unexpected number"},
+		},
+		{
+			"Should fail with an unexpected character",
+			"1A 2 OR",
+			&ParseError{Position: 1, Logic: "1A 2 OR", Reason: "unexpected character"},
+			&ParseError{Position: 1, Logic: "1A 2 OR", Reason: "unexpected character"},
+		},
+		{
+			"Should fail with a missing node",
+			"OR 1 AND 2",
+			&ParseError{Position: 5, Logic: "OR 1 AND 2", Reason: "unexpected operation"},
+			&ParseError{Position: 0, Logic: "OR 1 AND 2", Reason: "unexpected operation"},
+		},
+		{
+			"Should fail with too many leaves",
+			"1 2 3 OR",
+			&ParseError{Position: 3, Logic: "1 2 3 OR", Reason: "unexpected leaf"},
+			&ParseError{Position: 3, Logic: "1 2 3 OR", Reason: "unexpected leaf"},
+		},
+		{
+			"Should fail with a number that is too big",
+			"99999999999999999999999",
+			&ParseError{Position: 23, Logic: "99999999999999999999999", Reason: "99999999999999999999999 not an unsigned int"},
+			&ParseError{Position: 23, Logic: "99999999999999999999999", Reason: "99999999999999999999999 not an unsigned int"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			_, err := ParsePrefix(c.logic)
+			assert.Equal(t, c.prefix, err)
+
+			_, err = ParsePostfix(c.logic)
+			assert.Equal(t, c.postfix, err)
+		})
+	}
+}
+
+func TestNotationErrorPositions(t *testing.T) {
+	cases := []struct {
+		desc  string
+		logic string
+	}{
+		{
+			"Should point at the end of a stray leaf",
+			"1 2",
+		},
+		{
+			"Should point at the end of a long stray leaf",
+			"12 345",
+		},
+		{
+			"Should point at the start of a stray operation",
+			"AND",
+		},
+		{
+			"Should point at a bad character",
+			"1 & 2",
+		},
+		{
+			"Should point at an unexpected number",
+			"AN1D",
+		},
+		{
+			"Should point at an unexpected character",
+			"1A",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			_, expected := Parse(c.logic)
+			assert.Error(expected)
+
+			_, err := ParsePrefix(c.logic)
+			assert.Equal(expected, err)
+
+			_, err = ParsePostfix(c.logic)
+			assert.Equal(expected, err)
+		})
+	}
+}
+
+func TestNotationSerializeErrors(t *testing.T) {
+	n := &Op{Left: &Leaf{1}, Val: "XOR", Right: &Leaf{2}}
+
+	var b strings.Builder
+	assert.Equal(t, &SerializeError{Op: "XOR", Reason: "bad operation"}, EvalPrefix(n, &b))
+	assert.Equal(t, &SerializeError{Op: "XOR", Reason: "bad operation"}, EvalPostfix(n, &b))
+}
@@ -0,0 +1,91 @@
+package parse
+
+// Parenthesized will find every node that is wrapped in parentheses in the
+// logic, which Parse can't keep since a tree has nothing to hold them. The
+// paths are in the tree Parse builds, parents before their children.
+//	Parenthesized("(1 OR 2) AND (3 OR 4)")
+// Will yield /0 and /1
+func Parenthesized(logic string) ([]Path, error) {
+	n, err := Parse(logic)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := lex(logic)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &parenReader{
+		tokens: tokens,
+		marked: map[Node]bool{},
+	}
+	tree := r.level()
+
+	// Parse has already checked the logic, so the trees should always agree.
+	// If they somehow don't, no paths are better than wrong ones.
+	if !Equal(n, tree) {
+		return nil, nil
+	}
+
+	var paths []Path
+	WalkPaths(tree, func(c Node, p Path) {
+		if r.marked[c] {
+			paths = append(paths, p)
+		}
+	})
+	return paths, nil
+}
+
+// parenReader builds the same tree Parse does from logic Parse has already
+// checked, marking every node that was in parentheses
+type parenReader struct {
+	tokens []word
+	i      int
+	marked map[Node]bool
+}
+
+// level reads operands joined left to right until a closing parenthesis or
+// the end of the logic
+func (r *parenReader) level() Node {
+	n := r.operand()
+	for r.i < len(r.tokens) && r.tokens[r.i].text != ")" {
+		op := r.tokens[r.i].text
+		r.i++
+		n = &Op{
+			Left:  n,
+			Val:   op,
+			Right: r.operand(),
+		}
+	}
+	return n
+}
+
+func (r *parenReader) operand() Node {
+	if r.i == len(r.tokens) {
+		return nil
+	}
+
+	t := r.tokens[r.i]
+	r.i++
+
+	if t.text != "(" {
+		l, err := leaf("", t)
+		if err != nil {
+			return nil
+		}
+		return l
+	}
+
+	if r.i < len(r.tokens) && r.tokens[r.i].text == ")" {
+		r.i++
+		return nil
+	}
+
+	n := r.level()
+	r.i++
+	if n != nil {
+		r.marked[n] = true
+	}
+	return n
+}
@@ -0,0 +1,74 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParenthesized(t *testing.T) {
+	cases := []struct {
+		desc     string
+		logic    string
+		expected []string
+	}{
+		{
+			"Should find nothing without parentheses",
+			"1 OR 2 AND 3",
+			nil,
+		},
+		{
+			"Should find parentheses on the left",
+			"(1 OR 2) AND 3",
+			[]string{"/0"},
+		},
+		{
+			"Should find parentheses on both sides",
+			"(1 OR 2) AND (3 OR 4)",
+			[]string{"/0", "/1"},
+		},
+		{
+			"Should find nested parentheses",
+			"((1 AND 2) OR 3) AND 4",
+			[]string{"/0", "/0/0"},
+		},
+		{
+			"Should find parentheses deep on the left",
+			"1 AND (2 OR 3) OR 4",
+			[]string{"/0/1"},
+		},
+		{
+			"Should find parentheses around a leaf",
+			"(1) AND 2",
+			[]string{"/0"},
+		},
+		{
+			"Should find parentheses around everything",
+			"(1 AND 2)",
+			[]string{"/"},
+		},
+		{
+			"Should handle empty parentheses",
+			"1 AND ()",
+			nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			paths, err := Parenthesized(c.logic)
+			assert.NoError(t, err)
+
+			var actual []string
+			for _, p := range paths {
+				actual = append(actual, p.String())
+			}
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestParenthesizedErrors(t *testing.T) {
+	_, err := Parenthesized("1 AND")
+	assert.Equal(t, &ParseError{Position: 2, Logic: "1 AND", Reason: "unexpected operation"}, err)
+}
@@ -0,0 +1,84 @@
+package parse
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Path is the location of a node in a tree, given as the position of each
+// node on the way down from the root. The left node of an Op is 0 and the
+// right node is 1, and the nodes of a Group are numbered in order.
+type Path []int
+
+// String will print the path like /0/1, or / for the root
+func (p Path) String() string {
+	if len(p) == 0 {
+		return "/"
+	}
+
+	var b strings.Builder
+	for _, i := range p {
+		b.WriteString("/")
+		b.WriteString(strconv.Itoa(i))
+	}
+	return b.String()
+}
+
+// Child will return a new path for the ith node under p
+func (p Path) Child(i int) Path {
+	c := make(Path, len(p)+1)
+	copy(c, p)
+	c[len(p)] = i
+	return c
+}
+
+// At will return the node at path p in n, or nil if there isn't one
+func At(n Node, p Path) Node {
+	for _, i := range p {
+		switch node := n.(type) {
+		case *Op:
+			switch i {
+			case 0:
+				n = node.Left
+			case 1:
+				n = node.Right
+			default:
+				return nil
+			}
+		case *Group:
+			if i < 0 || i >= len(node.Nodes) {
+				return nil
+			}
+			n = node.Nodes[i]
+		default:
+			return nil
+		}
+	}
+	return n
+}
+
+// PathVisitor visits a node along with its path
+type PathVisitor func(Node, Path)
+
+// WalkPaths will visit every node in the tree, parents before their children
+func WalkPaths(n Node, visit PathVisitor) {
+	walkPaths(n, Path{}, visit)
+}
+
+func walkPaths(n Node, p Path, visit PathVisitor) {
+	if n == nil {
+		return
+	}
+
+	visit(n, p)
+
+	switch node := n.(type) {
+	case *Op:
+		walkPaths(node.Left, p.Child(0), visit)
+		walkPaths(node.Right, p.Child(1), visit)
+	case *Group:
+		for i, c := range node.Nodes {
+			walkPaths(c, p.Child(i), visit)
+		}
+	}
+}
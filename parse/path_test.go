@@ -0,0 +1,40 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPath(t *testing.T) {
+	assert := assert.New(t)
+
+	n, err := Parse("1 AND (2 OR 3)")
+	assert.NoError(err)
+
+	assert.Equal("/", Path{}.String())
+	assert.Equal("/1/0", Path{1, 0}.String())
+
+	deepEql(assert, n, At(n, Path{}))
+	deepEql(assert, &Leaf{1}, At(n, Path{0}))
+	deepEql(assert, &Leaf{3}, At(n, Path{1, 1}))
+	assert.Nil(At(n, Path{2}))
+	assert.Nil(At(n, Path{0, 0}))
+
+	g := Flatten(n)
+	deepEql(assert, &Leaf{2}, At(g, Path{1, 0}))
+	assert.Nil(At(g, Path{1, 2}))
+}
+
+func TestWalkPaths(t *testing.T) {
+	assert := assert.New(t)
+
+	n, err := Parse("1 AND 2 AND (3 OR 4)")
+	assert.NoError(err)
+
+	var paths []string
+	WalkPaths(Flatten(n), func(_ Node, p Path) {
+		paths = append(paths, p.String())
+	})
+	assert.Equal([]string{"/", "/0", "/1", "/2", "/2/0", "/2/1"}, paths)
+}
@@ -0,0 +1,92 @@
+package parse
+
+import (
+	"golang.org/x/tools/container/intsets"
+)
+
+// Report holds what CheckReferences found when comparing the leaves in a tree
+// with the conditions that are actually available
+type Report struct {
+	// Missing holds the leaf values that are not available conditions
+	Missing []uint
+	// Unreferenced holds the available conditions that no leaf points to
+	Unreferenced []uint
+	// Duplicates holds the leaf values that show up more than once
+	Duplicates []uint
+}
+
+// CheckReferences will compare the leaves in the tree with the conditions
+// that are available and report any that have drifted apart.
+//	n, _ := Parse("1 AND 7 OR 1")
+//	r := CheckReferences(n, []uint{1, 2, 3})
+// r.Missing will be [7], r.Unreferenced will be [2 3] and r.Duplicates [1]
+func CheckReferences(n Node, available []uint) Report {
+	s := Stats(n)
+
+	avail := &intsets.Sparse{}
+	for _, v := range available {
+		avail.Insert(int(v))
+	}
+
+	missing := &intsets.Sparse{}
+	missing.Difference(s.Leaves, avail)
+
+	unreferenced := &intsets.Sparse{}
+	unreferenced.Difference(avail, s.Leaves)
+
+	return Report{
+		Missing:      toUints(missing),
+		Unreferenced: toUints(unreferenced),
+		Duplicates:   toUints(s.Duplicates),
+	}
+}
+
+// OK will check that the report did not find anything wrong
+func (r Report) OK() bool {
+	return len(r.Missing) == 0 && len(r.Unreferenced) == 0 && len(r.Duplicates) == 0
+}
+
+// Fix will remove every missing leaf from the tree and then add every
+// unreferenced condition to the end of it with op. Duplicates are left alone,
+// since there is no telling which one was meant to stay. The tree will be nil
+// if there is nothing left in it.
+//	n, _ := Parse("1 AND 7")
+//	n, _ = CheckReferences(n, []uint{1, 2, 3}).Fix(n, "OR")
+// Will yield a tree for "1 OR 2 OR 3"
+func (r Report) Fix(n Node, op string) (Node, error) {
+	if len(r.Unreferenced) > 0 && !validOp(op) {
+		return nil, &EditError{
+			Op:     op,
+			Reason: "bad operation",
+		}
+	}
+
+	for _, v := range r.Missing {
+		if n == nil {
+			break
+		}
+		n = n.Remove(v)
+	}
+
+	for _, v := range r.Unreferenced {
+		if n == nil {
+			n = &Leaf{v}
+			continue
+		}
+		n = &Op{
+			Left:  n,
+			Val:   op,
+			Right: &Leaf{v},
+		}
+	}
+
+	return n, nil
+}
+
+func toUints(s *intsets.Sparse) []uint {
+	vals := make([]uint, 0, s.Len())
+	for _, v := range s.AppendTo(nil) {
+		vals = append(vals, uint(v))
+	}
+	return vals
+}
@@ -0,0 +1,106 @@
+package parse
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckReferences(t *testing.T) {
+	cases := []struct {
+		desc      string
+		logic     string
+		available []uint
+		expected  Report
+		fixed     string
+	}{
+		{
+			"Should not find anything wrong",
+			"1 AND (2 OR 3)",
+			[]uint{1, 2, 3},
+			Report{
+				Missing:      []uint{},
+				Unreferenced: []uint{},
+				Duplicates:   []uint{},
+			},
+			"1 AND (2 OR 3)",
+		},
+		{
+			"Should find missing references",
+			"1 AND (2 OR 7) AND 9",
+			[]uint{1, 2},
+			Report{
+				Missing:      []uint{7, 9},
+				Unreferenced: []uint{},
+				Duplicates:   []uint{},
+			},
+			"1 AND 2",
+		},
+		{
+			"Should find unreferenced conditions",
+			"1 AND 3",
+			[]uint{1, 2, 3, 4},
+			Report{
+				Missing:      []uint{},
+				Unreferenced: []uint{2, 4},
+				Duplicates:   []uint{},
+			},
+			"1 AND 3 OR 2 OR 4",
+		},
+		{
+			"Should find duplicates",
+			"1 AND 7 OR 1",
+			[]uint{1, 2, 3},
+			Report{
+				Missing:      []uint{7},
+				Unreferenced: []uint{2, 3},
+				Duplicates:   []uint{1},
+			},
+			"1 OR 1 OR 2 OR 3",
+		},
+		{
+			"Should rebuild the tree if everything was missing",
+			"7 AND 8",
+			[]uint{1, 2},
+			Report{
+				Missing:      []uint{7, 8},
+				Unreferenced: []uint{1, 2},
+				Duplicates:   []uint{},
+			},
+			"1 OR 2",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			n, err := Parse(c.logic)
+			assert.NoError(err)
+
+			r := CheckReferences(n, c.available)
+			assert.Equal(c.expected, r)
+			assert.Equal(len(c.expected.Missing)+len(c.expected.Unreferenced)+len(c.expected.Duplicates) == 0, r.OK())
+
+			fixed, err := r.Fix(n, "OR")
+			assert.NoError(err)
+
+			var b strings.Builder
+			assert.NoError(fixed.Eval(&b))
+			assert.Equal(c.fixed, b.String())
+		})
+	}
+}
+
+func TestFixErrors(t *testing.T) {
+	assert := assert.New(t)
+	n, err := Parse("1 AND 7")
+	assert.NoError(err)
+
+	_, err = CheckReferences(n, []uint{1, 2}).Fix(n, "FOO")
+	assert.Equal(&EditError{Op: "FOO", Reason: "bad operation"}, err)
+
+	fixed, err := CheckReferences(n, []uint{}).Fix(n, "")
+	assert.NoError(err)
+	assert.Nil(fixed)
+}
@@ -36,3 +36,33 @@ func (o *Op) Remove(v uint) Node {
 		Right: r,
 	}
 }
+
+// Remove a node by value from a group
+//	n.Remove(1)
+// Removes any leaf that has a value of 1, collapsing the group if it has one
+// or no nodes left
+func (g *Group) Remove(v uint) Node {
+
+	nodes := make([]Node, 0, len(g.Nodes))
+	for _, n := range g.Nodes {
+		if n == nil {
+			continue
+		}
+		if r := n.Remove(v); r != nil {
+			nodes = append(nodes, r)
+		}
+	}
+
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0]
+	}
+
+	return &Group{
+		Val:   g.Val,
+		Nodes: nodes,
+	}
+}
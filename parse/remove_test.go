@@ -144,6 +144,31 @@ func TestRemove(t *testing.T) {
 			[]uint{1},
 			"5 AND (2 OR 56 OR 4)",
 		},
+		{
+			"Should remove from a group",
+			&Group{
+				Val: "AND",
+				Nodes: []Node{
+					&Leaf{1},
+					&Group{
+						Val:   "OR",
+						Nodes: []Node{&Leaf{2}, &Leaf{3}, &Leaf{1}},
+					},
+					&Leaf{4},
+				},
+			},
+			[]uint{1, 3},
+			"2 AND 4",
+		},
+		{
+			"Should remove a whole group",
+			&Group{
+				Val:   "OR",
+				Nodes: []Node{&Leaf{1}, &Leaf{1}},
+			},
+			[]uint{1},
+			"",
+		},
 	}
 
 	for _, c := range cases {
@@ -44,7 +44,11 @@ func (o *Op) Eval(w io.Writer) error {
 		return err
 	}
 
-	_, parens := o.Right.(*Op)
+	var parens bool
+	switch o.Right.(type) {
+	case *Op, *Group:
+		parens = true
+	}
 
 	if parens {
 		fmt.Fprint(w, "(")
@@ -61,6 +65,63 @@ func (o *Op) Eval(w io.Writer) error {
 	return nil
 }
 
+// Eval will print every node in the group with the operation in between. It
+// writes the same thing as Eval on the binarized group would.
+func (g *Group) Eval(w io.Writer) error {
+	if len(g.Nodes) == 0 {
+		return &SerializeError{
+			Op:     g.Val,
+			Reason: "empty group",
+		}
+	}
+
+	if len(g.Nodes) > 1 && !validOp(g.Val) {
+		return &SerializeError{
+			Op:     g.Val,
+			Reason: "bad operation",
+		}
+	}
+
+	for i, n := range g.Nodes {
+		if n == nil {
+			return &SerializeError{
+				Op:     g.Val,
+				Reason: fmt.Sprintf("nil node at %d", i),
+			}
+		}
+
+		if i > 0 {
+			if _, err := fmt.Fprintf(w, " %s ", g.Val); err != nil {
+				return err
+			}
+		}
+
+		// Everything is read left to right, so only nodes after the first
+		// need parens
+		parens := false
+		if i > 0 {
+			switch n.(type) {
+			case *Op, *Group:
+				parens = true
+			}
+		}
+
+		if parens {
+			fmt.Fprint(w, "(")
+		}
+
+		if err := n.Eval(w); err != nil {
+			return err
+		}
+
+		if parens {
+			fmt.Fprint(w, ")")
+		}
+	}
+
+	return nil
+}
+
 // SerializeError holds information about syntactic errors when trying to eval
 type SerializeError struct {
 	Op     string
@@ -59,6 +59,38 @@ func TestSerializeErrors(t *testing.T) {
 				Reason: "bad operation",
 			},
 		},
+		{
+			"Should return an exception if the group is empty",
+			&Group{
+				Val: "AND",
+			},
+			&SerializeError{
+				Op:     "AND",
+				Reason: "empty group",
+			},
+		},
+		{
+			"Should return an exception if the group doesn't have an acceptable value",
+			&Group{
+				Val:   "FOO",
+				Nodes: []Node{&Leaf{1}, &Leaf{2}},
+			},
+			&SerializeError{
+				Op:     "FOO",
+				Reason: "bad operation",
+			},
+		},
+		{
+			"Should return an exception if the group has a nil node",
+			&Group{
+				Val:   "OR",
+				Nodes: []Node{&Leaf{1}, nil},
+			},
+			&SerializeError{
+				Op:     "OR",
+				Reason: "nil node at 1",
+			},
+		},
 	}
 
 	for _, c := range cases {
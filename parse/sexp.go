@@ -0,0 +1,202 @@
+package parse
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// EvalSexp will write the tree as an S-expression. An Op is written as
+// (AND 1 2) and a Group as [AND 1 2 3], so the tree reads back exactly as it
+// was. The tree isn't checked first, so a missing node is written as nil and
+// any operation is written as is, which makes it handy for debugging.
+//	n, _ := Parse("1 AND (2 OR 3)")
+//	EvalSexp(n, w)
+// Will write "(AND 1 (OR 2 3))"
+func EvalSexp(n Node, w io.Writer) error {
+	switch node := n.(type) {
+	case nil:
+		_, err := io.WriteString(w, "nil")
+		return err
+	case *Op:
+		if _, err := fmt.Fprintf(w, "(%s ", node.Val); err != nil {
+			return err
+		}
+		if err := EvalSexp(node.Left, w); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, " "); err != nil {
+			return err
+		}
+		if err := EvalSexp(node.Right, w); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, ")")
+		return err
+	case *Group:
+		if _, err := fmt.Fprintf(w, "[%s", node.Val); err != nil {
+			return err
+		}
+		for _, c := range node.Nodes {
+			if _, err := io.WriteString(w, " "); err != nil {
+				return err
+			}
+			if err := EvalSexp(c, w); err != nil {
+				return err
+			}
+		}
+		_, err := io.WriteString(w, "]")
+		return err
+	}
+	return n.Eval(w)
+}
+
+// Sexp will return the tree as an S-expression, the same way EvalSexp writes
+// it
+func Sexp(n Node) string {
+	var b strings.Builder
+	EvalSexp(n, &b)
+	return b.String()
+}
+
+// ParseSexp will read a tree written by EvalSexp. It builds exactly the tree
+// that was written, nil nodes and unknown operations included, so use Eval to
+// check it when that matters.
+//	ParseSexp("(AND 1 [OR 2 3 4])")
+// Will yield 1 <- AND -> OR[2 3 4]
+func ParseSexp(logic string) (Node, error) {
+	r := &sexpReader{logic: logic}
+	r.next()
+
+	if r.tok == "" {
+		return nil, nil
+	}
+
+	n, err := r.node()
+	if err != nil {
+		return nil, err
+	}
+
+	if r.tok != "" {
+		return nil, r.fail(fmt.Sprintf("unexpected %s", r.tok))
+	}
+	return n, nil
+}
+
+type sexpReader struct {
+	logic string
+	// tok is the current token and pos is where it starts. tok is empty at the
+	// end of the logic.
+	tok string
+	pos int
+	end int
+}
+
+func (r *sexpReader) fail(reason string) error {
+	return &ParseError{
+		Position: r.pos,
+		Logic:    r.logic,
+		Reason:   reason,
+	}
+}
+
+// next moves on to the next token
+func (r *sexpReader) next() {
+	i := r.end
+	for i < len(r.logic) && unicode.IsSpace(rune(r.logic[i])) {
+		i++
+	}
+
+	r.pos = i
+	r.end = i
+	if i == len(r.logic) {
+		r.tok = ""
+		return
+	}
+
+	c := rune(r.logic[i])
+	if unicode.IsLetter(c) || unicode.IsDigit(c) {
+		for r.end < len(r.logic) && (unicode.IsLetter(rune(r.logic[r.end])) || unicode.IsDigit(rune(r.logic[r.end]))) {
+			r.end++
+		}
+	} else {
+		r.end = i + 1
+	}
+	r.tok = r.logic[i:r.end]
+}
+
+func (r *sexpReader) node() (Node, error) {
+	switch {
+	case r.tok == "":
+		return nil, r.fail("unexpected end of logic")
+	case r.tok == "nil":
+		r.next()
+		return nil, nil
+	case r.tok == "(" || r.tok == "[":
+		return r.list()
+	case unicode.IsDigit(rune(r.tok[0])):
+		l, err := leaf(r.logic, word{text: r.tok, pos: r.pos, number: true})
+		if err != nil {
+			return nil, err
+		}
+		r.next()
+		return l, nil
+	}
+	return nil, r.fail(fmt.Sprintf("unexpected %s", r.tok))
+}
+
+// list reads an Op in parentheses or a Group in brackets
+func (r *sexpReader) list() (Node, error) {
+	open := r.tok
+	start := r.pos
+	closing := ")"
+	if open == "[" {
+		closing = "]"
+	}
+
+	r.next()
+	if r.tok == "" || !unicode.IsLetter(rune(r.tok[0])) || r.tok == "nil" {
+		return nil, r.fail("missing operation")
+	}
+	op := r.tok
+	r.next()
+
+	var nodes []Node
+	for r.tok != closing {
+		if r.tok == "" {
+			return nil, &ParseError{
+				Position: len(r.logic),
+				Logic:    r.logic,
+				Reason:   "unbalanced parenthesis",
+			}
+		}
+		n, err := r.node()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	r.next()
+
+	if open == "[" {
+		return &Group{
+			Val:   op,
+			Nodes: nodes,
+		}, nil
+	}
+
+	if len(nodes) != 2 {
+		return nil, &ParseError{
+			Position: start,
+			Logic:    r.logic,
+			Reason:   fmt.Sprintf("an operation needs two nodes, found %d", len(nodes)),
+		}
+	}
+
+	return &Op{
+		Left:  nodes[0],
+		Val:   op,
+		Right: nodes[1],
+	}, nil
+}
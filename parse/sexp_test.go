@@ -0,0 +1,173 @@
+package parse
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mustSexp reads a tree for a test case, panicking on bad fixtures
+func mustSexp(s string) Node {
+	n, err := ParseSexp(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func TestSexp(t *testing.T) {
+	cases := []struct {
+		desc     string
+		tree     Node
+		expected string
+	}{
+		{
+			"Should write a leaf",
+			&Leaf{1},
+			"1",
+		},
+		{
+			"Should write an operation",
+			&Op{
+				Left:  &Leaf{1},
+				Val:   "AND",
+				Right: &Op{&Leaf{2}, "OR", &Leaf{3}},
+			},
+			"(AND 1 (OR 2 3))",
+		},
+		{
+			"Should write a group",
+			&Group{
+				Val:   "OR",
+				Nodes: []Node{&Leaf{1}, &Op{&Leaf{2}, "AND", &Leaf{3}}, &Leaf{4}},
+			},
+			"[OR 1 (AND 2 3) 4]",
+		},
+		{
+			"Should write an empty group",
+			&Group{Val: "AND"},
+			"[AND]",
+		},
+		{
+			"Should write missing nodes and bad operations",
+			&Op{
+				Left: &Leaf{1},
+				Val:  "XOR",
+			},
+			"(XOR 1 nil)",
+		},
+		{
+			"Should write a nil tree",
+			nil,
+			"nil",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert.Equal(t, c.expected, Sexp(c.tree))
+
+			n, err := ParseSexp(c.expected)
+			assert.NoError(t, err)
+			assert.True(t, Equal(c.tree, n))
+		})
+	}
+}
+
+func TestParseSexp(t *testing.T) {
+	cases := []struct {
+		desc     string
+		logic    string
+		expected string
+	}{
+		{
+			"Should read like Parse",
+			"1 AND 2 OR (3 AND 4)",
+			"(OR (AND 1 2) (AND 3 4))",
+		},
+		{
+			"Should read extra parens",
+			"((1 AND 2) AND 5) OR (3 AND 4)",
+			"(OR (AND (AND 1 2) 5) (AND 3 4))",
+		},
+		{
+			"Should read any spacing",
+			"1 AND (2 OR 3)",
+			"  (AND\n\t1(OR 2 3 ) )",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			n, err := Parse(c.logic)
+			assert.NoError(t, err)
+			assert.True(t, Equal(mustSexp(c.expected), n), Sexp(n))
+		})
+	}
+}
+
+func TestParseSexpErrors(t *testing.T) {
+	cases := []struct {
+		desc  string
+		logic string
+		err   *ParseError
+	}{
+		{
+			"Should fail with a missing operation",
+			"(1 2)",
+			&ParseError{Position: 1, Logic: "(1 2)", Reason: "missing operation"},
+		},
+		{
+			"Should fail with too few nodes",
+			"(AND 1 (OR 2))",
+			&ParseError{Position: 7, Logic: "(AND 1 (OR 2))", Reason: "an operation needs two nodes, found 1"},
+		},
+		{
+			"Should fail with unbalanced parens",
+			"(AND 1 2",
+			&ParseError{Position: 8, Logic: "(AND 1 2", Reason: "unbalanced parenthesis"},
+		},
+		{
+			"Should fail with the wrong closing bracket",
+			"[AND 1 2)",
+			&ParseError{Position: 8, Logic: "[AND 1 2)", Reason: "unexpected )"},
+		},
+		{
+			"Should fail with anything after the tree",
+			"(AND 1 2) 3",
+			&ParseError{Position: 10, Logic: "(AND 1 2) 3", Reason: "unexpected 3"},
+		},
+		{
+			"Should fail with a bad leaf",
+			"(AND 1 2A)",
+			&ParseError{Position: 9, Logic: "(AND 1 2A)", Reason: "2A not an unsigned int"},
+		},
+		{
+			"Should fail with a bad character",
+			"(AND 1 !)",
+			&ParseError{Position: 7, Logic: "(AND 1 !)", Reason: "unexpected !"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			_, err := ParseSexp(c.logic)
+			assert.Equal(t, c.err, err)
+		})
+	}
+}
+
+func TestSexpRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	for i := 0; i < 200; i++ {
+		n := randomTree(r, 5, 8)
+		if i%2 == 0 {
+			n = Flatten(n)
+		}
+
+		back, err := ParseSexp(Sexp(n))
+		assert.NoError(t, err)
+		assert.True(t, Equal(n, back), Sexp(n))
+	}
+}
@@ -0,0 +1,91 @@
+package parse
+
+import (
+	"golang.org/x/tools/container/intsets"
+)
+
+// TreeStats holds what Stats found out about a tree
+type TreeStats struct {
+	// Leaves holds every distinct leaf value
+	Leaves *intsets.Sparse
+	// Duplicates holds every leaf value that shows up more than once
+	Duplicates *intsets.Sparse
+	// Counts is how many times each leaf value shows up
+	Counts map[uint]int
+	// LeafCount is the number of leaves, including duplicates
+	LeafCount int
+	// Min and Max are the lowest and highest leaf values. They are both 0 if
+	// there are no leaves.
+	Min uint
+	Max uint
+	// Depth is the number of nodes on the longest path from the root down to
+	// a leaf, so a single leaf has a depth of 1
+	Depth int
+	// Ops is how many times each operation shows up in the logic. A group
+	// counts once for every operation between its nodes, so a tree has the
+	// same counts whether it is flattened or not.
+	Ops map[string]int
+}
+
+// Stats will walk the tree once and collect the leaf values, how many times
+// each one shows up, the lowest and highest values, the depth of the tree and
+// how many times each operation is used.
+//	n, _ := Parse("1 AND 2 OR (1 AND 3)")
+//	s := Stats(n)
+// s.Duplicates will hold 1, s.Depth will be 3, s.Ops will be AND: 2, OR: 1
+func Stats(n Node) *TreeStats {
+	s := &TreeStats{
+		Leaves:     &intsets.Sparse{},
+		Duplicates: &intsets.Sparse{},
+		Counts:     map[uint]int{},
+		Ops:        map[string]int{},
+	}
+
+	s.Depth = s.walk(n)
+
+	if s.LeafCount > 0 {
+		s.Min = uint(s.Leaves.Min())
+		s.Max = uint(s.Leaves.Max())
+	}
+
+	return s
+}
+
+// HasDuplicates will check if any leaf value shows up more than once, which is
+// usually a sign that the logic was edited into something contradictory
+func (s *TreeStats) HasDuplicates() bool {
+	return !s.Duplicates.IsEmpty()
+}
+
+// walk adds the node to the stats and returns its depth
+func (s *TreeStats) walk(n Node) int {
+	switch node := n.(type) {
+	case *Leaf:
+		s.LeafCount++
+		s.Counts[node.Val]++
+		if !s.Leaves.Insert(int(node.Val)) {
+			s.Duplicates.Insert(int(node.Val))
+		}
+		return 1
+	case *Op:
+		s.Ops[node.Val]++
+		return 1 + max(s.walk(node.Left), s.walk(node.Right))
+	case *Group:
+		if len(node.Nodes) > 1 {
+			s.Ops[node.Val] += len(node.Nodes) - 1
+		}
+		depth := 0
+		for _, c := range node.Nodes {
+			depth = max(depth, s.walk(c))
+		}
+		return 1 + depth
+	}
+	return 0
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,103 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	cases := []struct {
+		desc       string
+		logic      string
+		leaves     []int
+		duplicates []int
+		leafCount  int
+		min        uint
+		max        uint
+		depth      int
+		ops        map[string]int
+	}{
+		{
+			"Should collect stats on a single leaf",
+			"5",
+			[]int{5},
+			[]int{},
+			1,
+			5,
+			5,
+			1,
+			map[string]int{},
+		},
+		{
+			"Should collect stats on a simple operation",
+			"3 AND 1",
+			[]int{1, 3},
+			[]int{},
+			2,
+			1,
+			3,
+			2,
+			map[string]int{"AND": 1},
+		},
+		{
+			"Should find duplicates",
+			"1 AND 2 OR (1 AND 3) OR 2",
+			[]int{1, 2, 3},
+			[]int{1, 2},
+			5,
+			1,
+			3,
+			4,
+			map[string]int{"AND": 2, "OR": 2},
+		},
+		{
+			"Should collect stats on a deep tree",
+			"1 OR (5 AND (7 OR 8)) AND (3 AND 2 OR (56 AND 1000) OR 4)",
+			[]int{1, 2, 3, 4, 5, 7, 8, 56, 1000},
+			[]int{},
+			9,
+			1,
+			1000,
+			5,
+			map[string]int{"AND": 4, "OR": 4},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			n, err := Parse(c.logic)
+			assert.NoError(err)
+
+			for _, tree := range []Node{n, Flatten(n)} {
+				s := Stats(tree)
+
+				assert.Equal(c.leaves, s.Leaves.AppendTo([]int{}))
+				assert.Equal(c.duplicates, s.Duplicates.AppendTo([]int{}))
+				assert.Equal(len(c.duplicates) > 0, s.HasDuplicates())
+				assert.Equal(c.leafCount, s.LeafCount)
+				assert.Equal(c.min, s.Min)
+				assert.Equal(c.max, s.Max)
+				assert.Equal(c.ops, s.Ops)
+			}
+
+			assert.Equal(c.depth, Stats(n).Depth)
+		})
+	}
+}
+
+func TestStatsCounts(t *testing.T) {
+	assert := assert.New(t)
+	n, err := Parse("1 AND 2 OR (1 AND 3) OR 1")
+	assert.NoError(err)
+
+	s := Stats(n)
+	assert.Equal(map[uint]int{1: 3, 2: 1, 3: 1}, s.Counts)
+	assert.Equal(3, Stats(Flatten(n)).Depth)
+
+	s = Stats(nil)
+	assert.Equal(0, s.LeafCount)
+	assert.Equal(0, s.Depth)
+	assert.False(s.HasDuplicates())
+}
@@ -25,6 +25,14 @@ type Op struct {
 	Right Node
 }
 
+// Group is a concrete Node that will hold a single operation applied to any
+// number of nodes. It is the n-ary, flattened form of a chain of Ops, so
+// "1 AND 2 AND 3" is one Group instead of two Ops. See Flatten and Binarize.
+type Group struct {
+	Val   string
+	Nodes []Node
+}
+
 // String is our stringer for pretty printing the tree. Well, ok, it is ugly
 // printing, but it is printing. It will take a tree and print something like:
 // 1 <- AND -> 2 <- OR -> 3
@@ -38,3 +46,14 @@ func (l *Leaf) String() string {
 func (o *Op) String() string {
 	return fmt.Sprintf("%v <- %s -> %v", o.Left, o.Val, o.Right)
 }
+
+// String is our stringer for pretty printing a group. It will print something
+// like: AND[1 OR[2 3] 4]
+func (g *Group) String() string {
+	return fmt.Sprintf("%s%v", g.Val, g.Nodes)
+}
+
+// validOp reports whether op is an operation we know how to parse and serialize
+func validOp(op string) bool {
+	return op == "AND" || op == "OR"
+}
@@ -0,0 +1,174 @@
+package parse
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/container/intsets"
+)
+
+// DefaultMaxLeaves is the most leaves TruthTable will allow if it is not told
+// otherwise. Every leaf doubles the number of rows.
+const DefaultMaxLeaves = 16
+
+// MaxLeaves is the most leaves TruthTable will ever allow, since any more and
+// the number of rows doesn't fit in an int
+const MaxLeaves = strconv.IntSize - 2
+
+// Table is the truth table of a tree, with a column for each distinct leaf
+type Table struct {
+	Leaves []uint
+	tree   Node
+	index  map[uint]int
+}
+
+// Row is a single row of a truth table. Values line up with Table.Leaves.
+type Row struct {
+	Values []bool
+	Result bool
+}
+
+// TruthTable will collect the distinct leaves in the tree, the same way
+// Sequence does, and make a table with a row for every assignment of them.
+// Rows are not built until Each is called. It returns a TableError if there
+// are more than maxLeaves leaves, or more than DefaultMaxLeaves if maxLeaves
+// is 0. A maxLeaves over MaxLeaves is lowered to MaxLeaves.
+//	n, _ := Parse("1 OR 2")
+//	t, _ := TruthTable(n, 0)
+// Will have the leaves [1 2] and 4 rows
+func TruthTable(n Node, maxLeaves int) (*Table, error) {
+	if maxLeaves <= 0 {
+		maxLeaves = DefaultMaxLeaves
+	}
+	if maxLeaves > MaxLeaves {
+		maxLeaves = MaxLeaves
+	}
+
+	leafs := &intsets.Sparse{}
+	WalkLeaves(n, initAddSet(leafs))
+
+	if leafs.Len() > maxLeaves {
+		return nil, &TableError{
+			Leaves: leafs.Len(),
+			Max:    maxLeaves,
+		}
+	}
+
+	t := &Table{
+		Leaves: make([]uint, 0, leafs.Len()),
+		tree:   n,
+		index:  map[uint]int{},
+	}
+	for i, v := range leafs.AppendTo(nil) {
+		t.Leaves = append(t.Leaves, uint(v))
+		t.index[uint(v)] = i
+	}
+
+	return t, nil
+}
+
+// Len is the number of rows in the table
+func (t *Table) Len() int {
+	return 1 << uint(len(t.Leaves))
+}
+
+// Each will build every row of the table in order, starting with every leaf
+// false and counting up with the last leaf changing fastest, and hand it to
+// visit. The row is reused, so copy its values to keep them around. It stops
+// at the first error visit returns.
+func (t *Table) Each(visit func(Row) error) error {
+	row := Row{
+		Values: make([]bool, len(t.Leaves)),
+	}
+	last := len(t.Leaves) - 1
+
+	for i := 0; i < t.Len(); i++ {
+		for j := range row.Values {
+			row.Values[j] = i&(1<<uint(last-j)) != 0
+		}
+		row.Result = Match(t.tree, func(v uint) bool {
+			return row.Values[t.index[v]]
+		})
+		if err := visit(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteCSV will write the table as CSV, with a header row holding the leaves
+// and a Result column. Values are written as T and F.
+func (t *Table) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(t.header()); err != nil {
+		return err
+	}
+
+	record := make([]string, len(t.Leaves)+1)
+	err := t.Each(func(r Row) error {
+		for i, v := range r.Values {
+			record[i] = tf(v)
+		}
+		record[len(r.Values)] = tf(r.Result)
+		return cw.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteMarkdown will write the table as a Markdown table, ready to paste into
+// a spec
+func (t *Table) WriteMarkdown(w io.Writer) error {
+	header := t.header()
+	if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(header, " | ")); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "|%s\n", strings.Repeat(" --- |", len(header))); err != nil {
+		return err
+	}
+
+	cells := make([]string, len(t.Leaves)+1)
+	return t.Each(func(r Row) error {
+		for i, v := range r.Values {
+			cells[i] = tf(v)
+		}
+		cells[len(r.Values)] = "**" + tf(r.Result) + "**"
+		_, err := fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+		return err
+	})
+}
+
+func (t *Table) header() []string {
+	header := make([]string, 0, len(t.Leaves)+1)
+	for _, v := range t.Leaves {
+		header = append(header, strconv.FormatUint(uint64(v), 10))
+	}
+	return append(header, "Result")
+}
+
+func tf(b bool) string {
+	if b {
+		return "T"
+	}
+	return "F"
+}
+
+// TableError holds information about a tree that is too big for a truth table
+type TableError struct {
+	Leaves int
+	Max    int
+}
+
+func (e *TableError) Error() string {
+	return fmt.Sprintf("Could not build truth table. Reason: %d leaves is more than the limit of %d", e.Leaves, e.Max)
+}
@@ -0,0 +1,115 @@
+package parse
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruthTable(t *testing.T) {
+	assert := assert.New(t)
+	n, err := Parse("3 OR 1 AND 3")
+	assert.NoError(err)
+
+	table, err := TruthTable(n, 0)
+	assert.NoError(err)
+	assert.Equal([]uint{1, 3}, table.Leaves)
+	assert.Equal(4, table.Len())
+
+	var rows []Row
+	err = table.Each(func(r Row) error {
+		rows = append(rows, Row{
+			Values: append([]bool{}, r.Values...),
+			Result: r.Result,
+		})
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal([]Row{
+		{[]bool{false, false}, false},
+		{[]bool{false, true}, true},
+		{[]bool{true, false}, false},
+		{[]bool{true, true}, true},
+	}, rows)
+}
+
+func TestTruthTableStop(t *testing.T) {
+	assert := assert.New(t)
+	n, err := Parse("1 AND 2 AND 3")
+	assert.NoError(err)
+
+	table, err := TruthTable(n, 0)
+	assert.NoError(err)
+
+	stop := errors.New("stop")
+	count := 0
+	err = table.Each(func(r Row) error {
+		count++
+		if r.Result {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(stop, err)
+	assert.Equal(8, count)
+}
+
+func TestTruthTableLimit(t *testing.T) {
+	assert := assert.New(t)
+	n, err := Parse("1 AND 2 AND 3 OR 2")
+	assert.NoError(err)
+
+	_, err = TruthTable(n, 2)
+	assert.Equal(&TableError{Leaves: 3, Max: 2}, err)
+
+	_, err = TruthTable(n, 3)
+	assert.NoError(err)
+
+	var wide Node = &Leaf{0}
+	for i := uint(1); i <= MaxLeaves; i++ {
+		wide = &Op{Left: wide, Val: "OR", Right: &Leaf{i}}
+	}
+
+	_, err = TruthTable(wide, 1000)
+	assert.Equal(&TableError{Leaves: MaxLeaves + 1, Max: MaxLeaves}, err)
+
+	table, err := TruthTable(wide.(*Op).Left, 1000)
+	assert.NoError(err)
+	assert.Equal(1<<MaxLeaves, table.Len())
+}
+
+func TestWriteCSV(t *testing.T) {
+	assert := assert.New(t)
+	n, err := Parse("1 OR 2")
+	assert.NoError(err)
+
+	table, err := TruthTable(n, 0)
+	assert.NoError(err)
+
+	var b strings.Builder
+	assert.NoError(table.WriteCSV(&b))
+	assert.Equal("1,2,Result\nF,F,F\nF,T,T\nT,F,T\nT,T,T\n", b.String())
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	assert := assert.New(t)
+	n, err := Parse("1 AND 2")
+	assert.NoError(err)
+
+	table, err := TruthTable(n, 0)
+	assert.NoError(err)
+
+	var b strings.Builder
+	assert.NoError(table.WriteMarkdown(&b))
+	assert.Equal(strings.Join([]string{
+		"| 1 | 2 | Result |",
+		"| --- | --- | --- |",
+		"| F | F | **F** |",
+		"| F | T | **F** |",
+		"| T | F | **F** |",
+		"| T | T | **T** |",
+		"",
+	}, "\n"), b.String())
+}
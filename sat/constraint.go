@@ -0,0 +1,69 @@
+package sat
+
+// Literal says that a leaf has a value
+type Literal struct {
+	Leaf  uint
+	Value bool
+}
+
+// Clause is satisfied if any one of its literals is
+type Clause []Literal
+
+// Constraint restricts which leaf values are allowed together. Anything that
+// can be written as a list of clauses, all of which must be satisfied, can be
+// a constraint.
+type Constraint interface {
+	Clauses() []Clause
+}
+
+// Clauses will return the clause itself, so any clause is a constraint
+func (c Clause) Clauses() []Clause {
+	return []Clause{c}
+}
+
+// Exclusive allows at most one of its leaves to be true, which is what you
+// get from conditions like "status = open" and "status = closed"
+type Exclusive []uint
+
+// Clauses will say that no two of the leaves can both be true
+func (e Exclusive) Clauses() []Clause {
+	var clauses []Clause
+	for i, a := range e {
+		for _, b := range e[i+1:] {
+			clauses = append(clauses, Clause{{a, false}, {b, false}})
+		}
+	}
+	return clauses
+}
+
+// AtLeastOne needs at least one of its leaves to be true
+type AtLeastOne []uint
+
+// Clauses will say that one of the leaves is true
+func (a AtLeastOne) Clauses() []Clause {
+	c := make(Clause, len(a))
+	for i, v := range a {
+		c[i] = Literal{v, true}
+	}
+	return []Clause{c}
+}
+
+// Implies needs Then to be true whenever If is, like "amount > 100" implies
+// "amount > 10"
+type Implies struct {
+	If   uint
+	Then uint
+}
+
+// Clauses will say that If is false or Then is true
+func (i Implies) Clauses() []Clause {
+	return []Clause{{{i.If, false}, {i.Then, true}}}
+}
+
+// Fixed pins a leaf to a value
+type Fixed Literal
+
+// Clauses will say that the leaf has the value
+func (f Fixed) Clauses() []Clause {
+	return []Clause{{Literal(f)}}
+}
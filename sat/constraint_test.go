@@ -0,0 +1,51 @@
+package sat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstraints(t *testing.T) {
+	cases := []struct {
+		desc       string
+		constraint Constraint
+		expected   []Clause
+	}{
+		{
+			"Should use a clause as is",
+			Clause{{1, true}, {2, false}},
+			[]Clause{{{1, true}, {2, false}}},
+		},
+		{
+			"Should rule out every pair of exclusive leaves",
+			Exclusive{1, 2, 3},
+			[]Clause{
+				{{1, false}, {2, false}},
+				{{1, false}, {3, false}},
+				{{2, false}, {3, false}},
+			},
+		},
+		{
+			"Should need one of the leaves",
+			AtLeastOne{1, 2, 3},
+			[]Clause{{{1, true}, {2, true}, {3, true}}},
+		},
+		{
+			"Should need then if the first leaf is true",
+			Implies{1, 2},
+			[]Clause{{{1, false}, {2, true}}},
+		},
+		{
+			"Should fix a leaf",
+			Fixed{4, false},
+			[]Clause{{{4, false}}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert.Equal(t, c.expected, c.constraint.Clauses())
+		})
+	}
+}
@@ -0,0 +1,140 @@
+// Package sat decides whether condition logic can ever match, or whether it
+// always matches, given constraints on which conditions can be true together.
+//
+// The tree is turned into clauses with one variable per leaf and one per
+// operation, and those are handed to a DPLL solver with unit propagation, so
+// it handles trees with hundreds of leaves without walking a truth table.
+package sat
+
+import (
+	"github.com/skuid/balsa/parse"
+)
+
+// Assignment holds a value for every leaf in the tree and the constraints
+type Assignment map[uint]bool
+
+// Satisfiable will check if there is any assignment of leaf values allowed by
+// the constraints that matches the tree, and return one if there is.
+//	n, _ := parse.Parse("1 AND 2")
+//	sat.Satisfiable(n, sat.Exclusive{1, 2})
+// Will return false, since 1 and 2 can't both be true
+func Satisfiable(n parse.Node, constraints ...Constraint) (Assignment, bool) {
+	return solve(n, true, constraints)
+}
+
+// Tautology will check if every assignment of leaf values allowed by the
+// constraints matches the tree. If one doesn't, it is returned along with
+// false.
+//	n, _ := parse.Parse("1 OR 2")
+//	sat.Tautology(n, sat.AtLeastOne{1, 2})
+// Will return true
+func Tautology(n parse.Node, constraints ...Constraint) (Assignment, bool) {
+	a, ok := solve(n, false, constraints)
+	if ok {
+		return a, false
+	}
+	return nil, true
+}
+
+// solve looks for an assignment where the tree has the given result
+func solve(n parse.Node, result bool, constraints []Constraint) (Assignment, bool) {
+	e := &encoder{vars: map[uint]int{}}
+
+	root := e.node(n)
+	if !result {
+		root = -root
+	}
+	e.clauses = append(e.clauses, []int{root})
+
+	for _, c := range constraints {
+		for _, clause := range c.Clauses() {
+			lits := make([]int, len(clause))
+			for i, l := range clause {
+				lits[i] = e.leaf(l.Leaf)
+				if !l.Value {
+					lits[i] = -lits[i]
+				}
+			}
+			e.clauses = append(e.clauses, lits)
+		}
+	}
+
+	s := newSolver(e.count, e.clauses)
+	if !s.solve() {
+		return nil, false
+	}
+
+	a := Assignment{}
+	for leaf, v := range e.vars {
+		a[leaf] = s.value[v] > 0
+	}
+	return a, true
+}
+
+// encoder turns a tree into clauses, giving each operation a variable that is
+// true exactly when the operation is
+type encoder struct {
+	vars    map[uint]int
+	count   int
+	clauses [][]int
+}
+
+func (e *encoder) newVar() int {
+	e.count++
+	return e.count
+}
+
+func (e *encoder) leaf(v uint) int {
+	if l, ok := e.vars[v]; ok {
+		return l
+	}
+	l := e.newVar()
+	e.vars[v] = l
+	return l
+}
+
+func (e *encoder) node(n parse.Node) int {
+	var op string
+	var children []parse.Node
+
+	switch node := n.(type) {
+	case *parse.Leaf:
+		return e.leaf(node.Val)
+	case *parse.Op:
+		op = node.Val
+		children = []parse.Node{node.Left, node.Right}
+	case *parse.Group:
+		op = node.Val
+		children = node.Nodes
+	default:
+		// Nothing at all never matches
+		f := e.newVar()
+		e.clauses = append(e.clauses, []int{-f})
+		return f
+	}
+
+	lits := make([]int, len(children))
+	for i, c := range children {
+		lits[i] = e.node(c)
+	}
+
+	g := e.newVar()
+	if op == "AND" {
+		// g is true if and only if every child is
+		all := []int{g}
+		for _, l := range lits {
+			e.clauses = append(e.clauses, []int{-g, l})
+			all = append(all, -l)
+		}
+		e.clauses = append(e.clauses, all)
+	} else {
+		// g is true if and only if any child is
+		some := []int{-g}
+		for _, l := range lits {
+			e.clauses = append(e.clauses, []int{g, -l})
+			some = append(some, l)
+		}
+		e.clauses = append(e.clauses, some)
+	}
+	return g
+}
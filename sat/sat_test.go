@@ -0,0 +1,265 @@
+package sat
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/skuid/balsa/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSatisfiable(t *testing.T) {
+	cases := []struct {
+		desc        string
+		logic       string
+		constraints []Constraint
+		expected    bool
+	}{
+		{
+			"Should satisfy a single leaf",
+			"1",
+			nil,
+			true,
+		},
+		{
+			"Should satisfy logic without constraints",
+			"1 AND 2 AND (3 OR 4)",
+			nil,
+			true,
+		},
+		{
+			"Should not satisfy exclusive leaves that are both needed",
+			"1 AND 2",
+			[]Constraint{Exclusive{1, 2}},
+			false,
+		},
+		{
+			"Should satisfy exclusive leaves with another way out",
+			"1 AND 2 OR 3",
+			[]Constraint{Exclusive{1, 2}},
+			true,
+		},
+		{
+			"Should not satisfy exclusive leaves in every branch",
+			"(1 OR 2) AND (3 OR 4) AND 5",
+			[]Constraint{Exclusive{1, 3, 5}, Exclusive{2, 4, 5}},
+			false,
+		},
+		{
+			"Should not satisfy a leaf fixed to false",
+			"1 AND (2 OR 3)",
+			[]Constraint{Fixed{1, false}},
+			false,
+		},
+		{
+			"Should not satisfy an implication that rules out the rest",
+			"1 AND 3",
+			[]Constraint{Implies{1, 2}, Exclusive{2, 3}},
+			false,
+		},
+		{
+			"Should not satisfy constraints that contradict each other",
+			"1 OR 2",
+			[]Constraint{Clause{}},
+			false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			n, err := parse.Parse(c.logic)
+			assert.NoError(err)
+
+			a, ok := Satisfiable(n, c.constraints...)
+			assert.Equal(c.expected, ok)
+			if ok {
+				assert.True(match(n, func(v uint) bool { return a[v] }), "Witness %v should match", a)
+				assert.True(allowed(a, c.constraints), "Witness %v should be allowed", a)
+			} else {
+				assert.Nil(a)
+			}
+		})
+	}
+}
+
+func TestTautology(t *testing.T) {
+	cases := []struct {
+		desc        string
+		logic       string
+		constraints []Constraint
+		expected    bool
+	}{
+		{
+			"Should not be a tautology without constraints",
+			"1 OR 2",
+			nil,
+			false,
+		},
+		{
+			"Should be a tautology when one leaf has to be true",
+			"1 OR 2",
+			[]Constraint{AtLeastOne{1, 2}},
+			true,
+		},
+		{
+			"Should be a tautology when every leaf is fixed",
+			"1 AND (2 OR 3)",
+			[]Constraint{Fixed{1, true}, Fixed{3, true}},
+			true,
+		},
+		{
+			"Should be a tautology through an implication",
+			"(1 OR 2) AND (3 OR 2)",
+			[]Constraint{AtLeastOne{1, 2}, Implies{1, 3}},
+			true,
+		},
+		{
+			"Should not be a tautology if a branch is left open",
+			"1 OR 2 AND 3",
+			[]Constraint{AtLeastOne{1, 2}},
+			false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			n, err := parse.Parse(c.logic)
+			assert.NoError(err)
+
+			a, ok := Tautology(n, c.constraints...)
+			assert.Equal(c.expected, ok)
+			if !ok {
+				assert.False(match(n, func(v uint) bool { return a[v] }), "Counterexample %v should not match", a)
+				assert.True(allowed(a, c.constraints), "Counterexample %v should be allowed", a)
+			} else {
+				assert.Nil(a)
+			}
+		})
+	}
+}
+
+func TestNilTree(t *testing.T) {
+	assert := assert.New(t)
+
+	_, ok := Satisfiable(nil)
+	assert.False(ok)
+
+	_, ok = Tautology(nil)
+	assert.False(ok)
+}
+
+// Check random trees against every assignment of their leaves
+func TestAgainstTruthTable(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		n := randomTree(r, 4, 6)
+		constraints := []Constraint{
+			Exclusive{uint(r.Intn(6)), uint(r.Intn(6))},
+			Implies{uint(r.Intn(6)), uint(r.Intn(6))},
+		}
+
+		sat, taut := false, true
+		for bits := 0; bits < 1<<6; bits++ {
+			a := Assignment{}
+			for v := uint(0); v < 6; v++ {
+				a[v] = bits&(1<<v) != 0
+			}
+			if !allowed(a, constraints) {
+				continue
+			}
+			if match(n, func(v uint) bool { return a[v] }) {
+				sat = true
+			} else {
+				taut = false
+			}
+		}
+
+		_, ok := Satisfiable(n, constraints...)
+		assert.Equal(t, sat, ok, "Satisfiable %v with %v", n, constraints)
+		_, ok = Tautology(n, constraints...)
+		assert.Equal(t, taut, ok, "Tautology %v with %v", n, constraints)
+	}
+}
+
+func TestManyLeaves(t *testing.T) {
+	assert := assert.New(t)
+
+	// (0 OR 1) AND (2 OR 3) AND ... with every pair of neighbors exclusive
+	// across groups, and the first of each pair ruled out
+	var b strings.Builder
+	var constraints []Constraint
+	for i := 0; i < 300; i += 2 {
+		if i > 0 {
+			b.WriteString(" AND ")
+		}
+		fmt.Fprintf(&b, "(%d OR %d)", i, i+1)
+		constraints = append(constraints, Fixed{uint(i), false})
+		if i > 0 {
+			constraints = append(constraints, Exclusive{uint(i - 1), uint(i + 1)})
+		}
+	}
+
+	n, err := parse.Parse(b.String())
+	assert.NoError(err)
+
+	_, ok := Satisfiable(n, constraints...)
+	assert.False(ok)
+
+	_, ok = Satisfiable(n, constraints[:len(constraints)-1]...)
+	assert.False(ok)
+
+	_, ok = Satisfiable(n, Exclusive{0, 2, 4, 6, 8})
+	assert.True(ok)
+}
+
+func allowed(a Assignment, constraints []Constraint) bool {
+	for _, c := range constraints {
+		for _, clause := range c.Clauses() {
+			ok := false
+			for _, l := range clause {
+				if a[l.Leaf] == l.Value {
+					ok = true
+				}
+			}
+			if !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// match evaluates a tree of leaves and ops left to right, the way the solver
+// should see it
+func match(n parse.Node, value func(uint) bool) bool {
+	switch node := n.(type) {
+	case *parse.Leaf:
+		return value(node.Val)
+	case *parse.Op:
+		if node.Val == "AND" {
+			return match(node.Left, value) && match(node.Right, value)
+		}
+		return match(node.Left, value) || match(node.Right, value)
+	}
+	return false
+}
+
+func randomTree(r *rand.Rand, depth int, leaves int) parse.Node {
+	if depth == 0 || r.Intn(3) == 0 {
+		return &parse.Leaf{Val: uint(r.Intn(leaves))}
+	}
+	op := "AND"
+	if r.Intn(2) == 0 {
+		op = "OR"
+	}
+	return &parse.Op{
+		Left:  randomTree(r, depth-1, leaves),
+		Val:   op,
+		Right: randomTree(r, depth-1, leaves),
+	}
+}
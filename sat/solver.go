@@ -0,0 +1,170 @@
+package sat
+
+import (
+	"sort"
+)
+
+// solver is a DPLL solver. Variables are numbered from 1 and a literal is a
+// variable that is negative when the variable should be false.
+type solver struct {
+	clauses [][]int
+	// occurs lists the clauses each literal is in, see index
+	occurs [][]int
+	// value is 1, -1 or 0 for true, false and not assigned yet
+	value []int8
+	trail []int
+	// order is the order to try variables in, most used first
+	order []int
+}
+
+func newSolver(count int, clauses [][]int) *solver {
+	s := &solver{
+		clauses: clauses,
+		occurs:  make([][]int, 2*(count+1)),
+		value:   make([]int8, count+1),
+	}
+
+	uses := make([]int, count+1)
+	for i, c := range clauses {
+		for _, l := range c {
+			s.occurs[index(l)] = append(s.occurs[index(l)], i)
+			uses[abs(l)]++
+		}
+	}
+
+	s.order = make([]int, count)
+	for i := range s.order {
+		s.order[i] = i + 1
+	}
+	sort.SliceStable(s.order, func(i, j int) bool {
+		return uses[s.order[i]] > uses[s.order[j]]
+	})
+
+	return s
+}
+
+func (s *solver) solve() bool {
+	// Start off with every unit clause, and bail on any empty one
+	for _, c := range s.clauses {
+		if len(c) == 0 {
+			return false
+		}
+		if len(c) == 1 && !s.assign(c[0]) {
+			return false
+		}
+	}
+	if !s.propagate(0) {
+		return false
+	}
+	return s.search()
+}
+
+func (s *solver) search() bool {
+	v := s.pick()
+	if v == 0 {
+		return true
+	}
+
+	for _, l := range []int{v, -v} {
+		mark := len(s.trail)
+		if s.assign(l) && s.propagate(mark) && s.search() {
+			return true
+		}
+		s.undo(mark)
+	}
+	return false
+}
+
+func (s *solver) pick() int {
+	for _, v := range s.order {
+		if s.value[v] == 0 {
+			return v
+		}
+	}
+	return 0
+}
+
+// assign makes the literal true, returning false if it already is false
+func (s *solver) assign(l int) bool {
+	switch s.lit(l) {
+	case 1:
+		return true
+	case -1:
+		return false
+	}
+
+	if l > 0 {
+		s.value[l] = 1
+	} else {
+		s.value[-l] = -1
+	}
+	s.trail = append(s.trail, l)
+	return true
+}
+
+// propagate goes through everything assigned since from, finding any clause
+// that is down to one literal and assigning it. It returns false on a clause
+// that can't be satisfied.
+func (s *solver) propagate(from int) bool {
+	for i := from; i < len(s.trail); i++ {
+		// The clauses that could have been broken are the ones holding the
+		// opposite literal
+		for _, ci := range s.occurs[index(-s.trail[i])] {
+			unit := 0
+			open := 0
+			satisfied := false
+			for _, l := range s.clauses[ci] {
+				switch s.lit(l) {
+				case 1:
+					satisfied = true
+				case 0:
+					open++
+					unit = l
+				}
+				if satisfied {
+					break
+				}
+			}
+			if satisfied {
+				continue
+			}
+			if open == 0 {
+				return false
+			}
+			if open == 1 && !s.assign(unit) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (s *solver) undo(mark int) {
+	for _, l := range s.trail[mark:] {
+		s.value[abs(l)] = 0
+	}
+	s.trail = s.trail[:mark]
+}
+
+// lit is the value of a literal, 1, -1 or 0
+func (s *solver) lit(l int) int8 {
+	if l > 0 {
+		return s.value[l]
+	}
+	return -s.value[-l]
+}
+
+// index is where a literal goes in occurs
+func index(l int) int {
+	if l > 0 {
+		return 2 * l
+	}
+	return -2*l + 1
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}